@@ -2,29 +2,45 @@ package cmd
 
 import (
 	"errors"
-	"fmt"
 	"github.com/freshautomations/stemplate/defaults"
 	"github.com/freshautomations/stemplate/exit"
+	"github.com/freshautomations/stemplate/render"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
-	"github.com/spf13/viper"
-	"io/ioutil"
 	"os"
-	"path/filepath"
-	"strconv"
 	"strings"
-	"text/template"
+	"time"
 )
 
 type FlagsType struct {
-	Env       bool
-	File      string
-	String    string
-	List      string
-	Map       string
-	Output    string
-	Extension string
-	All       bool
+	Env                bool
+	EnvPrefix          string
+	EnvNestedSeparator string
+	Dotenv             []string
+	File               []string
+	FileFormat         string
+	MergeLists         string
+	LowercaseKeys      bool
+	String             string
+	List               string
+	Map                string
+	ListSeparator      string
+	MapPairSeparator   string
+	JSON               string
+	YAML               string
+	Output             string
+	Extension          string
+	All                bool
+	Strict             bool
+	ClampStrings       bool
+	Partials           []string
+	Now                string
+	LeftDelim          string
+	RightDelim         string
+	Set                []string
+	SetString          []string
+	SetJSON            []string
+	SetFile            []string
 }
 
 var inputFlags FlagsType
@@ -35,317 +51,202 @@ func CheckArgs(cmd *cobra.Command, args []string) (err error) {
 		return
 	}
 
-	if inputFlags.File == "" && inputFlags.String == "" && inputFlags.List == "" && inputFlags.Map == "" && ! inputFlags.Env {
-		return errors.New("at least one of --file, --string, --list --env or --map is required")
+	if inputFlags.EnvNestedSeparator != "" && inputFlags.EnvPrefix == "" {
+		return errors.New("--env-nested-separator requires --env-prefix")
 	}
 
-	for _, item := range strings.Split(args[0], ",") {
-		_, err = os.Stat(item)
-		if err != nil {
-			return
-		}
+	if !hasDictionarySource() {
+		return errors.New("at least one of --file, --string, --list, --env, --env-prefix, --dotenv, --map, --json, --yaml or --set is required")
 	}
 
-	if inputFlags.File != "" {
-		_, err = os.Stat(inputFlags.File)
+	stdinUsers := 0
+	if args[0] == "-" {
+		stdinUsers++
+	} else {
+		for _, item := range strings.Split(args[0], ",") {
+			_, err = os.Stat(item)
+			if err != nil {
+				return
+			}
+		}
 	}
 
-	return err
-}
-
-func interface2uint64(input interface{}) (uint64, error) {
-	// Might be the right type already
-	if xnum, ok := input.(uint64); ok {
-		return xnum, nil
-	}
-	// JSON represents numbers as float64
-	if xnum, ok := input.(float64); ok {
-		return uint64(xnum), nil
+	for _, file := range inputFlags.File {
+		if file == "-" {
+			if inputFlags.FileFormat == "" {
+				return errors.New("--file-format is required when reading --file from stdin")
+			}
+			stdinUsers++
+			continue
+		}
+		_, err = os.Stat(file)
+		if err != nil {
+			return
+		}
 	}
-	// YAML represents numbers as int
-	if xnum, ok := input.(int); ok {
-		return uint64(xnum), nil
+	for _, file := range inputFlags.Dotenv {
+		_, err = os.Stat(file)
+		if err != nil {
+			return
+		}
 	}
-	// TOML represents numbers as int64
-	if xnum, ok := input.(int64); ok {
-		return uint64(xnum), nil
+	for _, dir := range inputFlags.Partials {
+		_, err = os.Stat(dir)
+		if err != nil {
+			return
+		}
 	}
-	// Some users might quote their numbers
-	if xnum, ok := input.(string); ok {
-		num, err := strconv.ParseUint(xnum, 10, 64)
-			return num, err
+	if stdinUsers > 1 {
+		return errors.New("only one of the template or a --file can be read from stdin")
 	}
-	return 0, errors.New(fmt.Sprintf("cannot convert input to number: %s", input))
-}
-
-var dictionary map[string]interface{}
 
-func substitute(name string) interface{} {
-	return dictionary[name]
-}
-
-func counter(input interface{}) (result []uint64, err error) {
-	var num uint64
-	num, err = interface2uint64(input)
-	var i uint64
-	for i = 0 ; i < num ; i++ {
-		result = append(result, i)
+	if inputFlags.FileFormat != "" {
+		if err = render.CheckFormat(inputFlags.FileFormat); err != nil {
+			return
+		}
 	}
-	return
-}
 
-func left(s string, input interface{}) (string, error) {
-	i, err := interface2uint64(input)
-	if err != nil {
-		return "", err
-	}
-	return s[0:i], err
+	return err
 }
 
-func right(s string, input interface{}) (string, error) {
-	i, err := interface2uint64(input)
-	if err != nil {
-		return "", err
-	}
-	return s[len(s)-int(i):], err
+// hasDictionarySource checks if at least one of the flags that fill the dictionary was set.
+func hasDictionarySource() bool {
+	return inputFlags.Env || inputFlags.EnvPrefix != "" || len(inputFlags.Dotenv) > 0 || len(inputFlags.File) > 0 ||
+		inputFlags.String != "" || inputFlags.List != "" || inputFlags.Map != "" || inputFlags.JSON != "" || inputFlags.YAML != "" ||
+		len(inputFlags.Set) > 0 || len(inputFlags.SetString) > 0 || len(inputFlags.SetJSON) > 0 || len(inputFlags.SetFile) > 0
 }
 
-func mid(s string, inputb interface{}, inputl interface{}) (string, error) {
-	b, err := interface2uint64(inputb)
-	if err != nil {
-		return "", err
-	}
-	l, err := interface2uint64(inputl)
-	if err != nil {
-		return "", err
+func RunRoot(cmd *cobra.Command, args []string) (output string, err error) {
+	// Priorities least to most: env, env-prefix, dotenv, file, string, list, map, json, yaml, set, set-string, set-json, set-file
+	dictionary := render.NewDictionary()
+	dictionary.LowercaseKeys = inputFlags.LowercaseKeys
+	dictionary.ListSeparator = inputFlags.ListSeparator
+	dictionary.MapPairSeparator = inputFlags.MapPairSeparator
+	if inputFlags.MergeLists != "" {
+		if dictionary.ListMerge, err = render.ParseMergeStrategy(inputFlags.MergeLists); err != nil {
+			return
+		}
 	}
-	return s[b:b+l], err
-}
 
-func add(a interface{}, b interface{}) (result uint64, err error) {
-	var ax, bx uint64
-	ax, err = interface2uint64(a)
-	if err != nil {
-		return
-	}
-	bx, err = interface2uint64(b)
-	if err != nil {
-		return
+	// Read --env
+	if inputFlags.Env {
+		dictionary.AddEnv()
 	}
-	result = ax + bx
-	return
-}
 
-func sub(a interface{}, b interface{}) (result uint64, err error) {
-	var ax, bx uint64
-	ax, err = interface2uint64(a)
-	if err != nil {
-		return
+	// Read --env-prefix and --env-nested-separator
+	if inputFlags.EnvPrefix != "" {
+		dictionary.AddEnvPrefix(inputFlags.EnvPrefix, inputFlags.EnvNestedSeparator)
 	}
-	bx, err = interface2uint64(b)
-	if err != nil {
-		return
-	}
-	result = ax - bx
-	return
-}
-
-func RunRoot(cmd *cobra.Command, args []string) (output string, err error) {
-// Priorities least to most: env, file, string, list, map
-
-	dictionary = make(map[string]interface{})
 
-	// Read --env
-	if inputFlags.Env {
-		for _, envVar := range os.Environ() {
-			equals := strings.Index(envVar,"=")
-			if equals < 1 || equals==len(envVar) {
-				// Invalid string
-				continue
-			}
-			name := envVar[0:equals]
-			value := envVar[equals+1:]
-			dictionary[name] = value
+	// Read --dotenv
+	for _, file := range inputFlags.Dotenv {
+		if err = dictionary.AddDotenv(file); err != nil {
+			return
 		}
 	}
 
-	// Read --file
-	if inputFlags.File != "" {
-		viper.SetConfigFile(inputFlags.File)
-		err = viper.ReadInConfig()
-		if err != nil {
-			if _, IsUnsupportedExtension := err.(viper.UnsupportedConfigError); IsUnsupportedExtension {
-				viper.SetConfigType("toml")
-				err = viper.ReadInConfig()
-				if err != nil {
-					return
-				}
-			} else {
-				return
-			}
-		}
-		for k, v := range viper.AllSettings() {
-			if dictionary[k] == nil {
-				dictionary[k] = v
-			}
+	// Read --file, later files are merged into earlier ones
+	for _, file := range inputFlags.File {
+		if err = addFile(dictionary, file); err != nil {
+			return
 		}
 	}
 
 	// Read --string
 	if inputFlags.String != "" {
-		for _, envVar := range strings.Split(inputFlags.String, ",") {
-			dictionary[envVar] = os.Getenv(envVar)
-		}
+		dictionary.AddStrings(strings.Split(inputFlags.String, ","))
 	}
 
 	// Read --list
 	if inputFlags.List != "" {
-		for _, envVar := range strings.Split(inputFlags.List, ",") {
-			dictionary[envVar] = strings.Split(os.Getenv(envVar), ",")
+		if err = dictionary.AddLists(strings.Split(inputFlags.List, ",")); err != nil {
+			return
 		}
 	}
+
 	// Read --map
 	if inputFlags.Map != "" {
-		for _, envVar := range strings.Split(inputFlags.Map, ",") {
-			tempMap := make(map[string]string)
-			for _, mapItem := range strings.Split(os.Getenv(envVar), ",") {
-				m := strings.Split(mapItem, "=")
-				if len(m) < 2 {
-					// something's not right, there's no equal sign (=) in the variable
-					return "", errors.New(fmt.Sprintf("Missing =. %s does not contain a map: %s", envVar, mapItem))
-				} else {
-					tempMap[m[0]] = strings.Join(m[1:], "=")
-				}
-			}
-			dictionary[envVar] = tempMap
+		if err = dictionary.AddMaps(strings.Split(inputFlags.Map, ",")); err != nil {
+			return
 		}
 	}
 
-	// Read and parse template files and directories
-	var tmpl *template.Template
-	funcMaps := template.FuncMap{
-		"substitute": substitute,
-		"counter": counter,
-		"left": left,
-		"right": right,
-		"mid": mid,
-		"add": add,
-		"sub": sub,
+	// Read --json
+	if inputFlags.JSON != "" {
+		if err = dictionary.AddJSON(strings.Split(inputFlags.JSON, ",")); err != nil {
+			return
+		}
 	}
 
-	// Input template
-	templateInput := args[0]
-	templateIsComplex := true // Assuming we have a list of files and directories
-	templateIsDir := false
-	if templateInfo, checkErr := os.Stat(templateInput); checkErr == nil {
-		templateIsDir = templateInfo.IsDir()
-		templateIsComplex = false
+	// Read --yaml
+	if inputFlags.YAML != "" {
+		if err = dictionary.AddYAML(strings.Split(inputFlags.YAML, ",")); err != nil {
+			return
+		}
 	}
 
-	// Output path
-	outputIsDir := false
-	if inputFlags.Output != "" {
-		outputInfo, checkErr := os.Stat(inputFlags.Output)
-		outputExist := checkErr == nil
-		if outputExist {
-			outputIsDir = outputInfo.IsDir()
+	// Read --set, --set-string, --set-json and --set-file
+	for _, expression := range inputFlags.Set {
+		if err = dictionary.AddSet(expression); err != nil {
+			return
 		}
-
-		if (templateIsComplex || templateIsDir) && !outputExist {
-			err = os.MkdirAll(inputFlags.Output, os.ModePerm)
-			if err != nil {
-				return
-			}
+	}
+	for _, expression := range inputFlags.SetString {
+		if err = dictionary.AddSetString(expression); err != nil {
+			return
 		}
-		if (templateIsComplex || templateIsDir) && outputExist && !outputIsDir {
-			err = errors.New("cannot copy template folder into file")
+	}
+	for _, expression := range inputFlags.SetJSON {
+		if err = dictionary.AddSetJSON(expression); err != nil {
+			return
+		}
+	}
+	for _, expression := range inputFlags.SetFile {
+		if err = dictionary.AddSetFile(expression); err != nil {
 			return
 		}
 	}
 
-	for _, templateFileOrDir := range strings.Split(templateInput, ",") {
-		err = filepath.Walk(templateFileOrDir, func(currentPath string, pathInfo os.FileInfo, err error) error {
-			if err != nil {
-				return err
-			}
-
-			var destination string
-			out := os.Stdout
-			if inputFlags.Output != "" {
-				// (file-to-file) source is a simple file, destination is a folder or a file
-				if !templateIsComplex && !templateIsDir {
-					if pathInfo.IsDir() { // source is under multiple folders
-						return nil
-					}
-					if outputIsDir {
-						destination = filepath.Join(inputFlags.Output, filepath.Base(currentPath))
-					} else {
-						destination = inputFlags.Output
-					}
-				}
-				// (dir-to-dir) source is one directory, use the contents only
-				if !templateIsComplex && templateIsDir {
-					relativeRoot := filepath.Clean(templateFileOrDir)
-					cleanCurrentPath := filepath.Clean(currentPath)
-					if currentPath == templateFileOrDir || relativeRoot == cleanCurrentPath { // do not copy the source's root folder
-						return nil
-					}
-					relativePath := filepath.Clean(strings.Replace(cleanCurrentPath, relativeRoot, "", 1))
-					destination = filepath.Join(inputFlags.Output, relativePath)
-				}
-				// (multi-to-dir) source is a list of files and directories, copy source folders too
-				if templateIsComplex {
-					destination = filepath.Join(inputFlags.Output, currentPath)
-				}
-				// if the current path is a directory, create it at output (should only run when multi|dir-to-dir)
-				if pathInfo.IsDir() {
-					return os.MkdirAll(destination, pathInfo.Mode())
-				}
-				// If extension does not match and we do not process all files in the template directory, then copy file and move on
-				if (templateIsComplex || templateIsDir) && !inputFlags.All && filepath.Ext(destination) != inputFlags.Extension {
-					return os.Link(currentPath, destination)
-				}
-				// Cut off .template extension
-				extension := filepath.Ext(destination)
-				if filepath.Ext(destination) == inputFlags.Extension {
-					destination = destination[0 : len(destination)-len(extension)]
-				}
-				// Create and open file
-				out, err = os.Create(destination)
-				defer out.Close()
-				if err != nil {
-					return err
-				}
-			} else { // Print to screen instead of file
-				// If the current path is a directory, move on
-				if pathInfo.IsDir() {
-					return nil
-				}
-				// If extension does not match and we do not process all files in the template directory, then print the file and move on
-				if (templateIsComplex || templateIsDir) && !inputFlags.All && filepath.Ext(currentPath) != inputFlags.Extension {
-					regularFileContent, openError := ioutil.ReadFile(currentPath)
-					_,_ = fmt.Fprint(out, regularFileContent)
-					return openError
-				}
-			}
-
-			// Prepare template reading
-			tmpl, err = template.New(filepath.Base(currentPath)).Funcs(funcMaps).ParseFiles(currentPath)
-			if err != nil {
-				return err
-			}
-
-			// Execute template and print results to destination output
-			return tmpl.Execute(out, dictionary)
-		})
-		if err != nil {
+	// Fixed time for reproducible renders: --now, then SOURCE_DATE_EPOCH
+	var now time.Time
+	timestamp := inputFlags.Now
+	if timestamp == "" {
+		timestamp = os.Getenv("SOURCE_DATE_EPOCH")
+	}
+	if timestamp != "" {
+		if now, err = render.ParseNow(timestamp); err != nil {
 			return
 		}
 	}
 
+	renderer := render.New(render.Options{
+		Extension:    inputFlags.Extension,
+		All:          inputFlags.All,
+		Output:       inputFlags.Output,
+		Strict:       inputFlags.Strict,
+		ClampStrings: inputFlags.ClampStrings,
+		Partials:     inputFlags.Partials,
+		LeftDelim:    inputFlags.LeftDelim,
+		RightDelim:   inputFlags.RightDelim,
+		Now:          now,
+	})
+	if args[0] == "-" {
+		err = renderer.RenderReader("stdin", os.Stdin, dictionary.Values())
+	} else {
+		err = renderer.Render(args[0], dictionary.Values())
+	}
 	return
 }
 
+// addFile reads a dictionary file, or stdin if file is "-". The format of stdin is set by --file-format,
+// the format of a file by its extension.
+func addFile(dictionary *render.Dictionary, file string) error {
+	if file == "-" {
+		return dictionary.AddReader("stdin", os.Stdin, inputFlags.FileFormat)
+	}
+	return dictionary.AddFile(file)
+}
+
 func runRootWrapper(cmd *cobra.Command, args []string) {
 	if result, err := RunRoot(cmd, args); err != nil {
 		exit.Fail(err)
@@ -366,13 +267,33 @@ Source and documentation is available at https://github.com/freshautomations/ste
 	}
 	rootCmd.Use = "stemplate <template>"
 	pflag.StringVarP(&inputFlags.Output, "output", "o", "", "Send results to this file instead of stdout")
-	pflag.StringVarP(&inputFlags.File, "file", "f", "", "Filename that contains data structure")
+	pflag.StringSliceVarP(&inputFlags.File, "file", "f", nil, "Filename that contains data structure. Repeat or comma-separate to merge multiple files")
+	pflag.BoolVar(&inputFlags.LowercaseKeys, "lowercase-keys", false, "Convert keys read from dictionary files to lowercase, like earlier versions did")
+	pflag.StringVar(&inputFlags.FileFormat, "file-format", "", "Format of the --file dictionary read from stdin (-): json, yaml or toml")
+	pflag.StringVar(&inputFlags.MergeLists, "merge-lists", "replace", "How lists are merged from multiple files: replace or append")
 	pflag.StringVarP(&inputFlags.String, "string", "s", "", "Comma-separated list of environment variable names that contain strings")
 	pflag.StringVarP(&inputFlags.List, "list", "l", "", "Comma-separated list of environment variable names that contain comma-separated strings")
 	pflag.StringVarP(&inputFlags.Map, "map", "m", "", "Comma-separated list of environment variable names that contain comma-separated strings of key=value pairs")
+	pflag.StringVar(&inputFlags.ListSeparator, "list-separator", render.DefaultSeparator, "Separator of the items in --list environment variables")
+	pflag.StringVar(&inputFlags.MapPairSeparator, "map-pair-separator", render.DefaultSeparator, "Separator of the key=value pairs in --map environment variables")
+	pflag.StringVar(&inputFlags.JSON, "json", "", "Comma-separated list of environment variable names that contain JSON values")
+	pflag.StringVar(&inputFlags.YAML, "yaml", "", "Comma-separated list of environment variable names that contain YAML values")
+	pflag.StringArrayVar(&inputFlags.Set, "set", nil, "Set a value at a key path (a.b.c=value). Numbers, true, false and null are converted. Can be repeated")
+	pflag.StringArrayVar(&inputFlags.SetString, "set-string", nil, "Set a string value at a key path (a.b.c=value). Can be repeated")
+	pflag.StringArrayVar(&inputFlags.SetJSON, "set-json", nil, "Set a JSON value at a key path (a.b.c='[1,2]'). Can be repeated")
+	pflag.StringArrayVar(&inputFlags.SetFile, "set-file", nil, "Set the content of a file at a key path (a.b.c=path). Can be repeated")
 	pflag.StringVarP(&inputFlags.Extension, "extension", "t", ".template", "Extension for template files when template input or output is a directory. Default: .template")
 	pflag.BoolVarP(&inputFlags.All, "all", "a", false, "Consider all files in a directory templates, regardless of extension.")
+	pflag.StringSliceVarP(&inputFlags.Partials, "partials", "p", nil, "Directory of templates that are parsed into every template. Repeat or comma-separate to use multiple directories")
+	pflag.StringVar(&inputFlags.LeftDelim, "left-delim", render.DefaultLeftDelim, "Left delimiter of template actions")
+	pflag.StringVar(&inputFlags.RightDelim, "right-delim", render.DefaultRightDelim, "Right delimiter of template actions")
+	pflag.StringVar(&inputFlags.Now, "now", "", "Fixed time for the now function, in Unix seconds or RFC 3339. Default: SOURCE_DATE_EPOCH or the current time")
+	pflag.BoolVar(&inputFlags.Strict, "strict", false, "Fail when a template refers to a missing key.")
+	pflag.BoolVar(&inputFlags.ClampStrings, "clamp-strings", false, "Limit the indices of left, right, mid and substr to the string instead of failing.")
 	pflag.BoolVarP(&inputFlags.Env, "env", "e", false, "Import all environment variables for templates as strings.")
+	pflag.StringVar(&inputFlags.EnvPrefix, "env-prefix", "", "Import environment variables that start with this prefix. The prefix is removed.")
+	pflag.StringVar(&inputFlags.EnvNestedSeparator, "env-nested-separator", "", "Convert the names of environment variables imported with --env-prefix to lowercase and split them into nested keys at this separator. Requires --env-prefix.")
+	pflag.StringSliceVar(&inputFlags.Dotenv, "dotenv", nil, "Dotenv file that contains KEY=value lines. Repeat or comma-separate to read multiple files")
 	_ = rootCmd.MarkFlagFilename("file")
 
 	return rootCmd.Execute()
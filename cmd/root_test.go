@@ -32,6 +32,25 @@ Welcome to custom functions demonstration.
 * right "abcdefg" 3: efg
 * string cut the last char from "abcdefg": abcdef
 * mid "abcdefg" 3 2: de
+* negative substraction 2 - 5: -3
+* multiplication 1.5 * 4: 6
+* division 7 / 2 and 7.0 / 2: 3 3.5
+* modulo 7 % 3: 1
+* min and max of 3 -1 2: -1 3
+* floor, ceil and round of 2.5: 2 3 3
+`
+
+var teststringsresult = `String library demonstration.
+
+* upper, lower and title: GUEST guest Hello World
+* trim: [spaces]
+* replace: not-a-test-map
+* split and join: a+b+c
+* join list: first, second, third
+* contains, hasPrefix and hasSuffix: true true false
+* repeat: ===
+* nindent:
+    not a test map
 `
 
 var testenvresult = `--env test
@@ -54,8 +73,24 @@ func TestCheckArgs(t *testing.T) {
 	assert.NotNil(t, CheckArgs(cmd, []string{filepath.Join(rootDir, "test_templates", " test.template"), filepath.Join("..", "test.json")}), "enough parameters")
 	assert.NotNil(t, CheckArgs(cmd, []string{"notexist.json"}), "file found")
 	assert.NotNil(t, CheckArgs(cmd, []string{filepath.Join(rootDir, "test_templates", "test.template")}), "file found")
-	inputFlags.File = filepath.Join(rootDir, "test_dictionaries", "test.json")
+	inputFlags.File = []string{filepath.Join(rootDir, "test_dictionaries", "test.json")}
 	assert.Nil(t, CheckArgs(cmd, []string{filepath.Join(rootDir, "test_templates", "test.template")}), "parameter check")
+	assert.Nil(t, CheckArgs(cmd, []string{"-"}), "template from stdin")
+	inputFlags.File = []string{"-"}
+	assert.NotNil(t, CheckArgs(cmd, []string{filepath.Join(rootDir, "test_templates", "test.template")}), "stdin without format")
+	inputFlags.FileFormat = "json"
+	assert.Nil(t, CheckArgs(cmd, []string{filepath.Join(rootDir, "test_templates", "test.template")}), "dictionary from stdin")
+	assert.NotNil(t, CheckArgs(cmd, []string{"-"}), "stdin used twice")
+	inputFlags.FileFormat = "ini"
+	assert.NotNil(t, CheckArgs(cmd, []string{filepath.Join(rootDir, "test_templates", "test.template")}), "unknown format")
+	inputFlags.FileFormat = ""
+	inputFlags.File = []string{filepath.Join(rootDir, "test_dictionaries", "test.json")}
+	inputFlags.EnvNestedSeparator = "__"
+	assert.NotNil(t, CheckArgs(cmd, []string{filepath.Join(rootDir, "test_templates", "test.template")}), "nested separator without prefix")
+	inputFlags.EnvPrefix = "APP_"
+	assert.Nil(t, CheckArgs(cmd, []string{filepath.Join(rootDir, "test_templates", "test.template")}), "nested separator with prefix")
+	inputFlags.EnvNestedSeparator = ""
+	inputFlags.EnvPrefix = ""
 }
 
 func TestRunRootSimpleFiles(t *testing.T) {
@@ -71,7 +106,7 @@ func TestRunRootSimpleFiles(t *testing.T) {
 
 	// JSON test
 	inputFlags.Output = "jsonresult.tmp"
-	inputFlags.File = filepath.Join(rootDir, "test_dictionaries","test.json")
+	inputFlags.File = []string{filepath.Join(rootDir, "test_dictionaries","test.json")}
 	_, err = RunRoot(cmd, []string{filepath.Join(rootDir, "test_templates", "test.template")})
 	assert.Nil(t, err, "unexpected error")
 	resultfile, err = ioutil.ReadFile(inputFlags.Output)
@@ -81,7 +116,7 @@ func TestRunRootSimpleFiles(t *testing.T) {
 
 	// TOML test
 	inputFlags.Output = "tomlresult.tmp"
-	inputFlags.File = filepath.Join(rootDir, "test_dictionaries","test.toml")
+	inputFlags.File = []string{filepath.Join(rootDir, "test_dictionaries","test.toml")}
 	_, err = RunRoot(cmd, []string{filepath.Join(rootDir, "test_templates", "test.template")})
 	assert.Nil(t, err, "unexpected error")
 	resultfile, err = ioutil.ReadFile(inputFlags.Output)
@@ -91,7 +126,7 @@ func TestRunRootSimpleFiles(t *testing.T) {
 
 	// YAML test
 	inputFlags.Output = "yamlresult.tmp"
-	inputFlags.File = filepath.Join(rootDir, "test_dictionaries","test.yaml")
+	inputFlags.File = []string{filepath.Join(rootDir, "test_dictionaries","test.yaml")}
 	_, err = RunRoot(cmd, []string{filepath.Join(rootDir, "test_templates","test.template")})
 	assert.Nil(t, err, "unexpected error")
 	resultfile, err = ioutil.ReadFile(inputFlags.Output)
@@ -101,7 +136,7 @@ func TestRunRootSimpleFiles(t *testing.T) {
 
 	// Environment variables test
 	inputFlags.Output = "emvresult.tmp"
-	inputFlags.File = ""
+	inputFlags.File = nil
 	inputFlags.String = "user,filename"
 	inputFlags.List = "list,gospecific"
 	inputFlags.Map = "map"
@@ -120,6 +155,155 @@ func TestRunRootSimpleFiles(t *testing.T) {
 
 }
 
+func TestRunRootSeparators(t *testing.T) {
+	cmd := &cobra.Command{
+		Args: func(cmd *cobra.Command, args []string) error {
+			return nil
+		},
+		Version: defaults.Version,
+	}
+	var resultfile []byte
+	var err error
+	inputFlags.Extension = ".template"
+
+	// Escaped and quoted separators
+	inputFlags.Output = "separatorresult.tmp"
+	inputFlags.File = nil
+	inputFlags.String = "user,filename"
+	inputFlags.List = "list,gospecific"
+	inputFlags.Map = "map"
+	_ = os.Setenv("user", "guest")
+	_ = os.Setenv("filename", "test")
+	_ = os.Setenv("list", `"first, quoted";second`)
+	_ = os.Setenv("gospecific", `Go;la\;ng`)
+	_ = os.Setenv("map", `test="test;map";nottest=not a testmap`)
+	inputFlags.ListSeparator = ";"
+	inputFlags.MapPairSeparator = ";"
+	_, err = RunRoot(cmd, []string{filepath.Join(rootDir, "test_templates", "test.template")})
+	assert.Nil(t, err, "unexpected error")
+	resultfile, err = ioutil.ReadFile(inputFlags.Output)
+	assert.Nil(t, err, "unexpected error")
+	assert.Contains(t, string(resultfile), "List item: first, quoted", "unexpected result")
+	assert.Contains(t, string(resultfile), "Map item: test;map", "unexpected result")
+	assert.Contains(t, string(resultfile), "Gola;ng specific stuff", "unexpected result")
+	_ = os.Remove(inputFlags.Output)
+
+	// Unterminated quote
+	_ = os.Setenv("list", `"first;second`)
+	_, err = RunRoot(cmd, []string{filepath.Join(rootDir, "test_templates", "test.template")})
+	assert.NotNil(t, err, "unterminated quote should fail")
+	_ = os.Remove(inputFlags.Output)
+	inputFlags.ListSeparator = ""
+	inputFlags.MapPairSeparator = ""
+	inputFlags.String = ""
+	inputFlags.List = ""
+	inputFlags.Map = ""
+
+}
+
+func TestRunRootMultipleFiles(t *testing.T) {
+	cmd := &cobra.Command{
+		Args: func(cmd *cobra.Command, args []string) error {
+			return nil
+		},
+		Version: defaults.Version,
+	}
+	var resultfile []byte
+	var err error
+	inputFlags.Extension = ".template"
+
+	inputFlags.Output = "mergeresult.tmp"
+	inputFlags.File = []string{filepath.Join(rootDir, "test_dictionaries", "test.json"), filepath.Join(rootDir, "test_dictionaries", "override.yaml")}
+	inputFlags.MergeLists = "append"
+	_, err = RunRoot(cmd, []string{filepath.Join(rootDir, "test_templates", "test.template")})
+	assert.Nil(t, err, "unexpected error")
+	resultfile, err = ioutil.ReadFile(inputFlags.Output)
+	assert.Nil(t, err, "unexpected error")
+	assert.Contains(t, string(resultfile), "Hi admin!", "unexpected result")
+	assert.Contains(t, string(resultfile), "Map item: overridden", "unexpected result")
+	_ = os.Remove(inputFlags.Output)
+	inputFlags.MergeLists = ""
+
+	// --file-format only applies to stdin
+	stdin := os.Stdin
+	os.Stdin, err = os.Open(filepath.Join(rootDir, "test_dictionaries", "override.yaml"))
+	assert.Nil(t, err, "unexpected error")
+	inputFlags.File = []string{filepath.Join(rootDir, "test_dictionaries", "test.json"), "-"}
+	inputFlags.FileFormat = "yaml"
+	_, err = RunRoot(cmd, []string{filepath.Join(rootDir, "test_templates", "test.template")})
+	assert.Nil(t, err, "unexpected error")
+	resultfile, err = ioutil.ReadFile(inputFlags.Output)
+	assert.Nil(t, err, "unexpected error")
+	assert.Contains(t, string(resultfile), "Hi admin!", "unexpected result")
+	_ = os.Stdin.Close()
+	os.Stdin = stdin
+	_ = os.Remove(inputFlags.Output)
+	inputFlags.FileFormat = ""
+
+}
+
+func TestRunRootSet(t *testing.T) {
+	cmd := &cobra.Command{
+		Args: func(cmd *cobra.Command, args []string) error {
+			return nil
+		},
+		Version: defaults.Version,
+	}
+	var resultfile []byte
+	var err error
+	inputFlags.Extension = ".template"
+
+	inputFlags.Output = "setresult.tmp"
+	inputFlags.File = []string{filepath.Join(rootDir, "test_dictionaries", "test.json")}
+	inputFlags.Set = []string{"map.test=settest"}
+	inputFlags.SetJSON = []string{`gospecific=["Java","Script"]`}
+	_, err = RunRoot(cmd, []string{filepath.Join(rootDir, "test_templates", "test.template")})
+	assert.Nil(t, err, "unexpected error")
+	resultfile, err = ioutil.ReadFile(inputFlags.Output)
+	assert.Nil(t, err, "unexpected error")
+	assert.Contains(t, string(resultfile), "Map item: settest", "unexpected result")
+	assert.Contains(t, string(resultfile), "JavaScript specific stuff", "unexpected result")
+	_ = os.Remove(inputFlags.Output)
+	inputFlags.Set = nil
+	inputFlags.SetJSON = nil
+
+}
+
+func TestRunRootDotenv(t *testing.T) {
+	cmd := &cobra.Command{
+		Args: func(cmd *cobra.Command, args []string) error {
+			return nil
+		},
+		Version: defaults.Version,
+	}
+	var resultfile []byte
+	var err error
+	inputFlags.Extension = ".template"
+
+	// Dotenv values are overridden by the file
+	inputFlags.Output = "dotenvresult.tmp"
+	inputFlags.Dotenv = []string{filepath.Join(rootDir, "test_dictionaries", "test.env")}
+	inputFlags.File = []string{filepath.Join(rootDir, "test_dictionaries", "test.json")}
+	_, err = RunRoot(cmd, []string{filepath.Join(rootDir, "test_templates", "test.template")})
+	assert.Nil(t, err, "unexpected error")
+	resultfile, err = ioutil.ReadFile(inputFlags.Output)
+	assert.Nil(t, err, "unexpected error")
+	assert.Contains(t, string(resultfile), "Welcome to this test template", "the file should override the dotenv file")
+
+	// Dotenv values are overridden by --string
+	_ = os.Setenv("user", "envuser")
+	inputFlags.String = "user"
+	_, err = RunRoot(cmd, []string{filepath.Join(rootDir, "test_templates", "test.template")})
+	assert.Nil(t, err, "unexpected error")
+	resultfile, err = ioutil.ReadFile(inputFlags.Output)
+	assert.Nil(t, err, "unexpected error")
+	assert.Contains(t, string(resultfile), "Hi envuser!", "unexpected result")
+	_ = os.Remove(inputFlags.Output)
+	inputFlags.Dotenv = nil
+	inputFlags.String = ""
+
+}
+
 func TestRunRootOutputDirectory(t *testing.T) {
 	cmd := &cobra.Command{
 		Args: func(cmd *cobra.Command, args []string) error {
@@ -133,7 +317,7 @@ func TestRunRootOutputDirectory(t *testing.T) {
 
 	// Output is a directory test
 	inputFlags.Output = filepath.Join(rootDir, "outputdir1")
-	inputFlags.File = filepath.Join(rootDir, "test_dictionaries","test.json")
+	inputFlags.File = []string{filepath.Join(rootDir, "test_dictionaries","test.json")}
 	err = os.Mkdir(inputFlags.Output, os.ModePerm)
 	assert.Nil(t, err, "unexpected error during folder creation")
 	_, err = RunRoot(cmd, []string{filepath.Join(rootDir, "test_templates","test.template")})
@@ -159,7 +343,7 @@ func TestRunRootInputDirectory(t *testing.T) {
 
 	// Input template is a folder, output is a directory test
 	inputFlags.Output = filepath.Join(rootDir, "outputdir2")
-	inputFlags.File = filepath.Join(rootDir, "test_dictionaries","test.json")
+	inputFlags.File = []string{filepath.Join(rootDir, "test_dictionaries","test.json")}
 	err = os.Mkdir(inputFlags.Output, os.ModePerm)
 	assert.Nil(t, err, "unexpected error during folder creation")
 	_, err = RunRoot(cmd, []string{filepath.Join(rootDir, "test_templates")})
@@ -187,7 +371,7 @@ func TestCustomFunctions(t *testing.T) {
 
 	// JSON test
 	inputFlags.Output = filepath.Join(rootDir, "customfunctions_jsonresult.tmp")
-	inputFlags.File = filepath.Join(rootDir, "test_dictionaries","test.json")
+	inputFlags.File = []string{filepath.Join(rootDir, "test_dictionaries","test.json")}
 	_, err = RunRoot(cmd, []string{filepath.Join(rootDir, "test_templates2","customfunctions.template")})
 	assert.Nil(t, err, "unexpected error")
 	resultfile, err = ioutil.ReadFile(inputFlags.Output)
@@ -197,7 +381,7 @@ func TestCustomFunctions(t *testing.T) {
 
 	// TOML test
 	inputFlags.Output = filepath.Join(rootDir, "customfunctions_tomlresult.tmp")
-	inputFlags.File = filepath.Join(rootDir, "test_dictionaries","test.toml")
+	inputFlags.File = []string{filepath.Join(rootDir, "test_dictionaries","test.toml")}
 	_, err = RunRoot(cmd, []string{filepath.Join(rootDir, "test_templates2","customfunctions.template")})
 	assert.Nil(t, err, "unexpected error")
 	resultfile, err = ioutil.ReadFile(inputFlags.Output)
@@ -207,7 +391,7 @@ func TestCustomFunctions(t *testing.T) {
 
 	// YAML test
 	inputFlags.Output = filepath.Join(rootDir, "customfunctions_yamlresult.tmp")
-	inputFlags.File = filepath.Join(rootDir, "test_dictionaries","test.yaml")
+	inputFlags.File = []string{filepath.Join(rootDir, "test_dictionaries","test.yaml")}
 	_, err = RunRoot(cmd, []string{filepath.Join(rootDir, "test_templates2","customfunctions.template")})
 	assert.Nil(t, err, "unexpected error")
 	resultfile, err = ioutil.ReadFile(inputFlags.Output)
@@ -217,6 +401,70 @@ func TestCustomFunctions(t *testing.T) {
 
 }
 
+func TestStringFunctions(t *testing.T) {
+	cmd := &cobra.Command{
+		Args: func(cmd *cobra.Command, args []string) error {
+			return nil
+		},
+		Version: defaults.Version,
+	}
+
+	var resultfile []byte
+	var err error
+
+	inputFlags.Extension = ".template"
+
+	inputFlags.Output = filepath.Join(rootDir, "strings_yamlresult.tmp")
+	inputFlags.File = []string{filepath.Join(rootDir, "test_dictionaries", "test.yaml")}
+	_, err = RunRoot(cmd, []string{filepath.Join(rootDir, "test_templates2", "strings.template")})
+	assert.Nil(t, err, "unexpected error")
+	resultfile, err = ioutil.ReadFile(inputFlags.Output)
+	assert.Nil(t, err, "unexpected error")
+	assert.Equal(t, teststringsresult, string(resultfile), "unexpected result")
+	_ = os.Remove(inputFlags.Output)
+
+}
+
+func TestNow(t *testing.T) {
+	cmd := &cobra.Command{
+		Args: func(cmd *cobra.Command, args []string) error {
+			return nil
+		},
+		Version: defaults.Version,
+	}
+
+	var resultfile []byte
+	var err error
+
+	inputFlags.Extension = ".template"
+	inputFlags.Output = filepath.Join(rootDir, "now_result.tmp")
+	inputFlags.File = []string{filepath.Join(rootDir, "test_dictionaries", "test.yaml")}
+
+	// SOURCE_DATE_EPOCH
+	_ = os.Setenv("SOURCE_DATE_EPOCH", "1700000000")
+	_, err = RunRoot(cmd, []string{filepath.Join(rootDir, "test_templates2", "now.template")})
+	assert.Nil(t, err, "unexpected error")
+	resultfile, err = ioutil.ReadFile(inputFlags.Output)
+	assert.Nil(t, err, "unexpected error")
+	assert.Equal(t, "generated at 2023-11-14T22:13:20Z\n", string(resultfile), "unexpected result")
+
+	// --now takes precedence
+	inputFlags.Now = "2024-01-02T03:04:05Z"
+	_, err = RunRoot(cmd, []string{filepath.Join(rootDir, "test_templates2", "now.template")})
+	assert.Nil(t, err, "unexpected error")
+	resultfile, err = ioutil.ReadFile(inputFlags.Output)
+	assert.Nil(t, err, "unexpected error")
+	assert.Equal(t, "generated at 2024-01-02T03:04:05Z\n", string(resultfile), "unexpected result")
+
+	inputFlags.Now = "tomorrow"
+	_, err = RunRoot(cmd, []string{filepath.Join(rootDir, "test_templates2", "now.template")})
+	assert.NotNil(t, err, "invalid time should fail")
+	_ = os.Remove(inputFlags.Output)
+	_ = os.Unsetenv("SOURCE_DATE_EPOCH")
+	inputFlags.Now = ""
+
+}
+
 func TestEnvParam(t *testing.T) {
 	cmd := &cobra.Command{
 		Args: func(cmd *cobra.Command, args []string) error {
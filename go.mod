@@ -3,8 +3,13 @@ module github.com/freshautomations/stemplate
 go 1.12
 
 require (
+	github.com/BurntSushi/toml v1.6.0 // indirect
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/inconshreveable/mousetrap v1.1.0 // indirect
+	github.com/pelletier/go-toml v1.2.0
+	github.com/pmezard/go-difflib v1.0.0 // indirect
 	github.com/spf13/cobra v0.0.3
 	github.com/spf13/pflag v1.0.3
-	github.com/spf13/viper v1.3.2
 	github.com/stretchr/testify v1.2.2
+	gopkg.in/yaml.v2 v2.2.2
 )
@@ -0,0 +1,354 @@
+package render
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// toList returns the items of a slice or array of any type. Nil is an empty list.
+func toList(input interface{}) ([]interface{}, error) {
+	if input == nil {
+		return []interface{}{}, nil
+	}
+	value := reflect.ValueOf(input)
+	if value.Kind() != reflect.Slice && value.Kind() != reflect.Array {
+		return nil, errors.New(fmt.Sprintf("not a list: %v", input))
+	}
+	result := make([]interface{}, value.Len())
+	for i := range result {
+		result[i] = value.Index(i).Interface()
+	}
+	return result, nil
+}
+
+// toDict returns a copy of a map of any type with string keys. Nil is an empty map.
+func toDict(input interface{}) (map[string]interface{}, error) {
+	if input == nil {
+		return map[string]interface{}{}, nil
+	}
+	value := reflect.ValueOf(input)
+	if value.Kind() != reflect.Map {
+		return nil, errors.New(fmt.Sprintf("not a map: %v", input))
+	}
+	result := make(map[string]interface{}, value.Len())
+	for _, key := range value.MapKeys() {
+		result[fmt.Sprint(key.Interface())] = value.MapIndex(key).Interface()
+	}
+	return result, nil
+}
+
+func list(items ...interface{}) []interface{} {
+	return append([]interface{}{}, items...)
+}
+
+// dict returns a map built from key and value pairs: {{ dict "name" .name "port" 80 }}
+func dict(pairs ...interface{}) (map[string]interface{}, error) {
+	if len(pairs)%2 != 0 {
+		return nil, errors.New("dict needs an even number of arguments")
+	}
+	result := make(map[string]interface{}, len(pairs)/2)
+	for i := 0; i < len(pairs); i += 2 {
+		result[fmt.Sprint(pairs[i])] = pairs[i+1]
+	}
+	return result, nil
+}
+
+// appendItem returns a copy of items with value added at the end. Use it in a pipeline: {{ .hosts | append "localhost" }}
+func appendItem(value interface{}, items interface{}) ([]interface{}, error) {
+	result, err := toList(items)
+	if err != nil {
+		return nil, err
+	}
+	return append(result, value), nil
+}
+
+// prependItem returns a copy of items with value added at the beginning.
+func prependItem(value interface{}, items interface{}) ([]interface{}, error) {
+	result, err := toList(items)
+	if err != nil {
+		return nil, err
+	}
+	return append([]interface{}{value}, result...), nil
+}
+
+// concat returns the items of all the lists in a single list.
+func concat(lists ...interface{}) ([]interface{}, error) {
+	result := []interface{}{}
+	for _, items := range lists {
+		values, err := toList(items)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, values...)
+	}
+	return result, nil
+}
+
+// uniq returns the items of a list without duplicates, in the order they first appear.
+func uniq(items interface{}) ([]interface{}, error) {
+	values, err := toList(items)
+	if err != nil {
+		return nil, err
+	}
+	result := []interface{}{}
+	for _, value := range values {
+		duplicate := false
+		for _, seen := range result {
+			if reflect.DeepEqual(value, seen) {
+				duplicate = true
+				break
+			}
+		}
+		if !duplicate {
+			result = append(result, value)
+		}
+	}
+	return result, nil
+}
+
+// sortAlpha returns the items of a list as strings, sorted alphabetically.
+func sortAlpha(items interface{}) ([]string, error) {
+	values, err := toList(items)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]string, len(values))
+	for i, value := range values {
+		result[i] = fmt.Sprint(value)
+	}
+	sort.Strings(result)
+	return result, nil
+}
+
+// less compares two values as numbers if both are numbers, as strings otherwise.
+func less(a interface{}, b interface{}) bool {
+	if ia, ib, fa, fb, ints, err := toNumbers(a, b); err == nil {
+		if ints {
+			return ia < ib
+		}
+		return fa < fb
+	}
+	return fmt.Sprint(a) < fmt.Sprint(b)
+}
+
+// sortBy returns a list of maps sorted by the value of key. The order of maps with equal values is kept.
+func sortBy(key string, items interface{}) ([]interface{}, error) {
+	values, err := toList(items)
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]interface{}, len(values))
+	for i, value := range values {
+		item, err := toDict(value)
+		if err != nil {
+			return nil, err
+		}
+		keys[i] = item[key]
+	}
+	indices := make([]int, len(values))
+	for i := range indices {
+		indices[i] = i
+	}
+	sort.SliceStable(indices, func(i, j int) bool {
+		return less(keys[indices[i]], keys[indices[j]])
+	})
+	result := make([]interface{}, len(values))
+	for i, index := range indices {
+		result[i] = values[index]
+	}
+	return result, nil
+}
+
+func reverse(items interface{}) ([]interface{}, error) {
+	values, err := toList(items)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]interface{}, len(values))
+	for i, value := range values {
+		result[len(values)-1-i] = value
+	}
+	return result, nil
+}
+
+// first returns the first item of a list, or nil if the list is empty.
+func first(items interface{}) (interface{}, error) {
+	values, err := toList(items)
+	if err != nil || len(values) == 0 {
+		return nil, err
+	}
+	return values[0], nil
+}
+
+// last returns the last item of a list, or nil if the list is empty.
+func last(items interface{}) (interface{}, error) {
+	values, err := toList(items)
+	if err != nil || len(values) == 0 {
+		return nil, err
+	}
+	return values[len(values)-1], nil
+}
+
+// rest returns all the items of a list but the first.
+func rest(items interface{}) ([]interface{}, error) {
+	values, err := toList(items)
+	if err != nil || len(values) == 0 {
+		return []interface{}{}, err
+	}
+	return values[1:], nil
+}
+
+// slice returns the items of a list, or the characters of a string, from start up to but not including end.
+// End defaults to the length. Like the built-in slice, a list takes a third index that limits the capacity.
+// Unlike the built-in slice, strings are cut at character (rune) boundaries and the indices can be any number type.
+func slice(items interface{}, indices ...interface{}) (interface{}, error) {
+	s, isString := items.(string)
+	var values []interface{}
+	var runes []rune
+	var length int
+	if isString {
+		runes = []rune(s)
+		length = len(runes)
+	} else {
+		var err error
+		if values, err = toList(items); err != nil {
+			return nil, err
+		}
+		length = len(values)
+	}
+	if len(indices) > 3 || (isString && len(indices) > 2) {
+		return nil, errors.New("slice needs a start and an optional end index, and a list takes an optional capacity")
+	}
+	bounds := []int{0, length, length}
+	for i, index := range indices {
+		bound, err := toInt(index)
+		if err != nil {
+			return nil, err
+		}
+		bounds[i] = bound
+	}
+	if bounds[0] < 0 || bounds[0] > bounds[1] || bounds[1] > bounds[2] || bounds[2] > length {
+		return nil, errors.New(fmt.Sprintf("slice indices out of range [%d:%d:%d] with length %d", bounds[0], bounds[1], bounds[2], length))
+	}
+	if isString {
+		return string(runes[bounds[0]:bounds[1]]), nil
+	}
+	return values[bounds[0]:bounds[1]:bounds[2]], nil
+}
+
+// keys returns the keys of a map, sorted.
+func keys(input interface{}) ([]string, error) {
+	values, err := toDict(input)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]string, 0, len(values))
+	for key := range values {
+		result = append(result, key)
+	}
+	sort.Strings(result)
+	return result, nil
+}
+
+// values returns the values of a map, in the order of the sorted keys.
+func values(input interface{}) ([]interface{}, error) {
+	dictionary, err := toDict(input)
+	if err != nil {
+		return nil, err
+	}
+	sortedKeys, _ := keys(dictionary)
+	result := make([]interface{}, len(sortedKeys))
+	for i, key := range sortedKeys {
+		result[i] = dictionary[key]
+	}
+	return result, nil
+}
+
+// pick returns a copy of a map with only the given keys.
+func pick(input interface{}, names ...string) (map[string]interface{}, error) {
+	dictionary, err := toDict(input)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string]interface{}, len(names))
+	for _, name := range names {
+		if value, ok := dictionary[name]; ok {
+			result[name] = value
+		}
+	}
+	return result, nil
+}
+
+// omit returns a copy of a map without the given keys.
+func omit(input interface{}, names ...string) (map[string]interface{}, error) {
+	result, err := toDict(input)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range names {
+		delete(result, name)
+	}
+	return result, nil
+}
+
+// hasKey checks if a map has key. Use it in a pipeline: {{ if .tls | hasKey "cert" }}
+func hasKey(key string, input interface{}) (bool, error) {
+	dictionary, err := toDict(input)
+	if err != nil {
+		return false, err
+	}
+	_, ok := dictionary[key]
+	return ok, nil
+}
+
+// merge returns the deep merge of maps. Later maps override earlier ones, like dictionary sources.
+// The maps themselves are not changed.
+func merge(maps ...interface{}) (map[string]interface{}, error) {
+	result := map[string]interface{}{}
+	for _, input := range maps {
+		dictionary, err := toDict(input)
+		if err != nil {
+			return nil, err
+		}
+		if err = mergeInto(result, dictionary); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// mergeInto deep merges src into dst. Nested maps in dst are copies, so src is never changed later.
+func mergeInto(dst map[string]interface{}, src map[string]interface{}) error {
+	for key, value := range src {
+		if reflect.ValueOf(value).Kind() != reflect.Map {
+			dst[key] = value
+			continue
+		}
+		nested, ok := dst[key].(map[string]interface{})
+		if !ok {
+			nested = map[string]interface{}{}
+		}
+		srcNested, err := toDict(value)
+		if err != nil {
+			return err
+		}
+		if err = mergeInto(nested, srcNested); err != nil {
+			return err
+		}
+		dst[key] = nested
+	}
+	return nil
+}
+
+// set returns a copy of a map with key set to value. Use it in a pipeline: {{ .labels | set "tier" "web" }}
+// The dictionary is shared by all templates, so the map itself is not changed.
+func set(key string, value interface{}, input interface{}) (map[string]interface{}, error) {
+	result, err := toDict(input)
+	if err != nil {
+		return nil, err
+	}
+	result[key] = value
+	return result, nil
+}
@@ -0,0 +1,161 @@
+package render
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListFunctions(t *testing.T) {
+	strs := []string{"b", "a", "b"}
+
+	result, err := appendItem("c", strs)
+	assert.Nil(t, err, "unexpected error")
+	assert.Equal(t, []interface{}{"b", "a", "b", "c"}, result, "unexpected result")
+	assert.Equal(t, []string{"b", "a", "b"}, strs, "append changed its input")
+
+	result, err = prependItem(1, []interface{}{2})
+	assert.Nil(t, err, "unexpected error")
+	assert.Equal(t, []interface{}{1, 2}, result, "unexpected result")
+
+	result, err = concat(strs, nil, []interface{}{1})
+	assert.Nil(t, err, "unexpected error")
+	assert.Equal(t, []interface{}{"b", "a", "b", 1}, result, "unexpected result")
+
+	result, err = uniq(strs)
+	assert.Nil(t, err, "unexpected error")
+	assert.Equal(t, []interface{}{"b", "a"}, result, "unexpected result")
+
+	sorted, err := sortAlpha([]interface{}{"b", 10, "a"})
+	assert.Nil(t, err, "unexpected error")
+	assert.Equal(t, []string{"10", "a", "b"}, sorted, "unexpected result")
+
+	result, err = reverse(strs[:2])
+	assert.Nil(t, err, "unexpected error")
+	assert.Equal(t, []interface{}{"a", "b"}, result, "unexpected result")
+
+	item, err := first(strs)
+	assert.Nil(t, err, "unexpected error")
+	assert.Equal(t, "b", item, "unexpected result")
+	item, err = last([]interface{}{1, 2})
+	assert.Nil(t, err, "unexpected error")
+	assert.Equal(t, 2, item, "unexpected result")
+	item, err = first([]string{})
+	assert.Nil(t, err, "unexpected error")
+	assert.Nil(t, item, "unexpected result")
+
+	result, err = rest(strs)
+	assert.Nil(t, err, "unexpected error")
+	assert.Equal(t, []interface{}{"a", "b"}, result, "unexpected result")
+	result, err = rest(nil)
+	assert.Nil(t, err, "unexpected error")
+	assert.Equal(t, []interface{}{}, result, "unexpected result")
+
+	sliced, err := slice(strs, float64(1))
+	assert.Nil(t, err, "unexpected error")
+	assert.Equal(t, []interface{}{"a", "b"}, sliced, "unexpected result")
+	sliced, err = slice("hello", 1, int64(3))
+	assert.Nil(t, err, "unexpected error")
+	assert.Equal(t, "el", sliced, "unexpected result")
+	sliced, err = slice("Árvíz", 0, 1)
+	assert.Nil(t, err, "unexpected error")
+	assert.Equal(t, "Á", sliced, "strings should be cut at character boundaries")
+	sliced, err = slice("Árvíz", 3)
+	assert.Nil(t, err, "unexpected error")
+	assert.Equal(t, "íz", sliced, "unexpected result")
+	sliced, err = slice([]int{1, 2, 3}, 0, 1, 2)
+	assert.Nil(t, err, "unexpected error")
+	assert.Equal(t, []interface{}{1}, sliced, "unexpected result")
+	assert.Equal(t, 2, cap(sliced.([]interface{})), "unexpected capacity")
+
+	for _, f := range []func() error{
+		func() error { _, err := appendItem("a", "b"); return err },
+		func() error { _, err := concat([]string{}, map[string]string{}); return err },
+		func() error { _, err := slice(strs, 2, 1); return err },
+		func() error { _, err := slice(strs, 4); return err },
+		func() error { _, err := slice(strs, 0, 1, 4); return err },
+		func() error { _, err := slice(strs, 0, 2, 1); return err },
+		func() error { _, err := slice(strs, 0, 1, 2, 3); return err },
+		func() error { _, err := slice("abc", 0, 1, 2); return err },
+		func() error { _, err := slice("Árvíz", 6); return err },
+		func() error { _, err := slice(strs, "x"); return err },
+		func() error { _, err := sortBy("name", strs); return err },
+	} {
+		assert.NotNil(t, f(), "expected error")
+	}
+}
+
+func TestSortBy(t *testing.T) {
+	items := []interface{}{
+		map[string]interface{}{"name": "web", "port": int64(8080)},
+		map[interface{}]interface{}{"name": "db", "port": 5432},
+		map[string]string{"name": "cache", "port": "6379"},
+		map[string]interface{}{"name": "api", "port": float64(8080)},
+	}
+	result, err := sortBy("port", items)
+	assert.Nil(t, err, "unexpected error")
+	assert.Equal(t, []interface{}{items[1], items[2], items[0], items[3]}, result, "unexpected result")
+	result, err = sortBy("name", items)
+	assert.Nil(t, err, "unexpected error")
+	assert.Equal(t, []interface{}{items[3], items[2], items[1], items[0]}, result, "unexpected result")
+}
+
+func TestMapFunctions(t *testing.T) {
+	labels := map[string]string{"app": "web", "tier": "frontend"}
+
+	d, err := dict("a", 1, "b", "two")
+	assert.Nil(t, err, "unexpected error")
+	assert.Equal(t, map[string]interface{}{"a": 1, "b": "two"}, d, "unexpected result")
+
+	names, err := keys(labels)
+	assert.Nil(t, err, "unexpected error")
+	assert.Equal(t, []string{"app", "tier"}, names, "unexpected result")
+	result, err := values(labels)
+	assert.Nil(t, err, "unexpected error")
+	assert.Equal(t, []interface{}{"web", "frontend"}, result, "unexpected result")
+
+	d, err = pick(labels, "app", "missing")
+	assert.Nil(t, err, "unexpected error")
+	assert.Equal(t, map[string]interface{}{"app": "web"}, d, "unexpected result")
+	d, err = omit(labels, "app")
+	assert.Nil(t, err, "unexpected error")
+	assert.Equal(t, map[string]interface{}{"tier": "frontend"}, d, "unexpected result")
+
+	ok, err := hasKey("tier", labels)
+	assert.Nil(t, err, "unexpected error")
+	assert.True(t, ok, "unexpected result")
+	ok, err = hasKey("missing", labels)
+	assert.Nil(t, err, "unexpected error")
+	assert.False(t, ok, "unexpected result")
+
+	d, err = set("tier", "backend", labels)
+	assert.Nil(t, err, "unexpected error")
+	assert.Equal(t, map[string]interface{}{"app": "web", "tier": "backend"}, d, "unexpected result")
+	assert.Equal(t, "frontend", labels["tier"], "set changed its input")
+
+	defaults := map[string]interface{}{"image": map[string]interface{}{"name": "nginx", "tag": "latest"}, "replicas": 1}
+	overrides := map[string]interface{}{"image": map[string]string{"tag": "1.19"}}
+	d, err = merge(defaults, overrides)
+	assert.Nil(t, err, "unexpected error")
+	assert.Equal(t, map[string]interface{}{"image": map[string]interface{}{"name": "nginx", "tag": "1.19"}, "replicas": 1}, d, "unexpected result")
+	assert.Equal(t, "latest", defaults["image"].(map[string]interface{})["tag"], "merge changed its input")
+
+	for _, f := range []func() error{
+		func() error { _, err := dict("a"); return err },
+		func() error { _, err := keys("a"); return err },
+		func() error { _, err := hasKey("a", []string{}); return err },
+		func() error { _, err := merge(labels, "a"); return err },
+	} {
+		assert.NotNil(t, f(), "expected error")
+	}
+}
+
+func TestCollectionsTemplate(t *testing.T) {
+	var out bytes.Buffer
+	r := New(Options{Writer: &out})
+	err := r.RenderReader("collections", bytes.NewBufferString(`{{ range .hosts | append "localhost" | uniq }}{{ . }} {{ end }}{{ $labels := .labels | set "tier" "web" }}{{ range $k, $v := $labels }}{{ $k }}={{ $v }} {{ end }}{{ slice .hosts 1 | first }}`),
+		map[string]interface{}{"hosts": []string{"a", "b", "a"}, "labels": map[string]string{"app": "x"}})
+	assert.Nil(t, err, "unexpected error")
+	assert.Equal(t, "a b localhost app=x tier=web b", out.String(), "unexpected result")
+}
@@ -0,0 +1,96 @@
+package render
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParseNow converts a timestamp to a time for Options.Now. It accepts Unix seconds or RFC 3339.
+// Unix seconds are in UTC, as SOURCE_DATE_EPOCH expects.
+func ParseNow(timestamp string) (time.Time, error) {
+	if seconds, err := strconv.ParseInt(timestamp, 10, 64); err == nil {
+		return time.Unix(seconds, 0).UTC(), nil
+	}
+	result, err := time.Parse(time.RFC3339, timestamp)
+	if err != nil {
+		return time.Time{}, errors.New(fmt.Sprintf("invalid time, use Unix seconds or RFC 3339: %s", timestamp))
+	}
+	return result, nil
+}
+
+// nowFunc returns the now function. A fixed time makes the rendering reproducible.
+func nowFunc(fixed time.Time) func() time.Time {
+	return func() time.Time {
+		if fixed.IsZero() {
+			return time.Now()
+		}
+		return fixed
+	}
+}
+
+// toTime converts a time, Unix seconds or an RFC 3339 string to a time.
+func toTime(input interface{}) (time.Time, error) {
+	switch value := input.(type) {
+	case time.Time:
+		return value, nil
+	case *time.Time:
+		return *value, nil
+	case string:
+		return ParseNow(value)
+	}
+	number, err := toNumber(input)
+	if err != nil {
+		return time.Time{}, errors.New(fmt.Sprintf("cannot convert input to time: %v", input))
+	}
+	if seconds, ok := number.(int64); ok {
+		return time.Unix(seconds, 0).UTC(), nil
+	}
+	seconds := number.(float64)
+	return time.Unix(0, int64(seconds*float64(time.Second))).UTC(), nil
+}
+
+// date formats t with a Go time layout: {{ now | date "2006-01-02" }}
+func date(layout string, t interface{}) (string, error) {
+	value, err := toTime(t)
+	if err != nil {
+		return "", err
+	}
+	return value.Format(layout), nil
+}
+
+// dateModify adds a duration to t. The duration is a Go duration, like "-1h30m", or a number of days, like "30d".
+func dateModify(modifier string, t interface{}) (time.Time, error) {
+	value, err := toTime(t)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if strings.HasSuffix(modifier, "d") {
+		days, err := strconv.ParseFloat(strings.TrimSuffix(modifier, "d"), 64)
+		if err != nil {
+			return time.Time{}, errors.New(fmt.Sprintf("invalid duration: %s", modifier))
+		}
+		return value.Add(time.Duration(days * float64(24*time.Hour))), nil
+	}
+	duration, err := time.ParseDuration(modifier)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return value.Add(duration), nil
+}
+
+// unixEpoch returns t in Unix seconds.
+func unixEpoch(t interface{}) (int64, error) {
+	value, err := toTime(t)
+	if err != nil {
+		return 0, err
+	}
+	return value.Unix(), nil
+}
+
+// toDate parses value with a Go time layout.
+func toDate(layout string, value string) (time.Time, error) {
+	return time.Parse(layout, value)
+}
@@ -0,0 +1,64 @@
+package render
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseNow(t *testing.T) {
+	result, err := ParseNow("1700000000")
+	assert.Nil(t, err, "unexpected error")
+	assert.Equal(t, time.Date(2023, 11, 14, 22, 13, 20, 0, time.UTC), result, "unexpected time")
+
+	result, err = ParseNow("2023-11-14T22:13:20+01:00")
+	assert.Nil(t, err, "unexpected error")
+	assert.Equal(t, int64(1699996400), result.Unix(), "unexpected time")
+
+	_, err = ParseNow("yesterday")
+	assert.NotNil(t, err, "invalid time should fail")
+}
+
+func TestDateFunctions(t *testing.T) {
+	fixed := time.Date(2023, 11, 14, 22, 13, 20, 0, time.UTC)
+	assert.Equal(t, fixed, nowFunc(fixed)(), "fixed time expected")
+	assert.False(t, nowFunc(time.Time{})().IsZero(), "current time expected")
+
+	result, err := date("2006-01-02", fixed)
+	assert.Nil(t, err, "unexpected error")
+	assert.Equal(t, "2023-11-14", result, "unexpected date")
+	result, err = date("15:04", 1700000000)
+	assert.Nil(t, err, "unexpected error")
+	assert.Equal(t, "22:13", result, "unexpected date")
+	_, err = date("15:04", []int{})
+	assert.NotNil(t, err, "invalid time should fail")
+
+	modified, err := dateModify("30d", fixed)
+	assert.Nil(t, err, "unexpected error")
+	assert.Equal(t, time.Date(2023, 12, 14, 22, 13, 20, 0, time.UTC), modified, "unexpected date")
+	modified, err = dateModify("-1h30m", fixed)
+	assert.Nil(t, err, "unexpected error")
+	assert.Equal(t, time.Date(2023, 11, 14, 20, 43, 20, 0, time.UTC), modified, "unexpected date")
+	_, err = dateModify("xd", fixed)
+	assert.NotNil(t, err, "invalid duration should fail")
+
+	epoch, err := unixEpoch(fixed)
+	assert.Nil(t, err, "unexpected error")
+	assert.Equal(t, int64(1700000000), epoch, "unexpected epoch")
+
+	parsed, err := toDate("2006-01-02", "2023-11-14")
+	assert.Nil(t, err, "unexpected error")
+	assert.Equal(t, time.Date(2023, 11, 14, 0, 0, 0, 0, time.UTC), parsed, "unexpected date")
+}
+
+func TestRenderNow(t *testing.T) {
+	var result bytes.Buffer
+	now := time.Date(2023, 11, 14, 22, 13, 20, 0, time.UTC)
+	err := New(Options{Writer: &result, Now: now}).
+		RenderReader("stdin", strings.NewReader(`generated at {{ now | date "2006-01-02T15:04:05Z07:00" }}, expires at {{ now | dateModify "7d" | unixEpoch }}`), nil)
+	assert.Nil(t, err, "unexpected error")
+	assert.Equal(t, "generated at 2023-11-14T22:13:20Z, expires at 1700604800", result.String(), "unexpected result")
+}
@@ -0,0 +1,123 @@
+package render
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/pelletier/go-toml"
+	"gopkg.in/yaml.v2"
+)
+
+// Supported dictionary formats.
+const (
+	FormatJSON = "json"
+	FormatYAML = "yaml"
+	FormatTOML = "toml"
+)
+
+// FormatFromPath returns the dictionary format of a file based on its extension.
+// Files with an unknown extension are considered TOML.
+func FormatFromPath(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return FormatJSON
+	case ".yaml", ".yml":
+		return FormatYAML
+	}
+	return FormatTOML
+}
+
+// CheckFormat returns an error if format is not a supported dictionary format.
+func CheckFormat(format string) error {
+	switch format {
+	case FormatJSON, FormatYAML, FormatTOML:
+		return nil
+	}
+	return errors.New(fmt.Sprintf("unknown format: %s", format))
+}
+
+// Decode parses a JSON, YAML or TOML document that contains a map. Key case is preserved.
+func Decode(data []byte, format string) (map[string]interface{}, error) {
+	result, err := DecodeValue(data, format)
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		// Empty document
+		return make(map[string]interface{}), nil
+	}
+	resultMap, ok := result.(map[string]interface{})
+	if !ok {
+		return nil, errors.New(fmt.Sprintf("%s document does not contain a map", format))
+	}
+	return resultMap, nil
+}
+
+// DecodeValue parses a JSON, YAML or TOML document that contains any value. TOML documents always contain a map.
+// Maps are returned as map[string]interface{}, lists as []interface{}.
+func DecodeValue(data []byte, format string) (interface{}, error) {
+	var result interface{}
+	switch format {
+	case FormatJSON:
+		if err := json.Unmarshal(data, &result); err != nil {
+			return nil, err
+		}
+	case FormatYAML:
+		if err := yaml.Unmarshal(data, &result); err != nil {
+			return nil, err
+		}
+	case FormatTOML:
+		tree, err := toml.LoadBytes(data)
+		if err != nil {
+			return nil, err
+		}
+		return tree.ToMap(), nil
+	default:
+		return nil, CheckFormat(format)
+	}
+	return normalize(result), nil
+}
+
+// normalize converts the map[interface{}]interface{} values produced by YAML decoding to map[string]interface{}.
+func normalize(input interface{}) interface{} {
+	switch value := input.(type) {
+	case map[interface{}]interface{}:
+		result := make(map[string]interface{}, len(value))
+		for k, v := range value {
+			result[fmt.Sprint(k)] = normalize(v)
+		}
+		return result
+	case map[string]interface{}:
+		for k, v := range value {
+			value[k] = normalize(v)
+		}
+		return value
+	case []interface{}:
+		for i, v := range value {
+			value[i] = normalize(v)
+		}
+		return value
+	}
+	return input
+}
+
+// lowercaseKeys converts all map keys to lowercase, recursively.
+func lowercaseKeys(input interface{}) interface{} {
+	switch value := input.(type) {
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(value))
+		for k, v := range value {
+			result[strings.ToLower(k)] = lowercaseKeys(v)
+		}
+		return result
+	case []interface{}:
+		for i, v := range value {
+			value[i] = lowercaseKeys(v)
+		}
+		return value
+	}
+	return input
+}
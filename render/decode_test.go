@@ -0,0 +1,44 @@
+package render
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodePreservesCase(t *testing.T) {
+	for _, file := range []string{"test.json", "test.toml", "test.yaml"} {
+		path := filepath.Join(rootDir, "test_dictionaries", file)
+		data, err := ioutil.ReadFile(path)
+		assert.Nil(t, err, "unexpected error")
+		values, err := Decode(data, FormatFromPath(path))
+		assert.Nil(t, err, "unexpected error for "+file)
+		assert.Equal(t, "camelCase", values["accountId"], "key case not preserved for "+file)
+		assert.Equal(t, "testmap", values["map"].(map[string]interface{})["test"], "nested map not decoded for "+file)
+	}
+}
+
+func TestDecodeYAMLNestedMaps(t *testing.T) {
+	values, err := Decode([]byte("list:\n  - name: first\n    1: one\n"), FormatYAML)
+	assert.Nil(t, err, "unexpected error")
+	assert.Equal(t, []interface{}{map[string]interface{}{"name": "first", "1": "one"}}, values["list"], "unexpected result")
+
+	values, err = Decode([]byte(""), FormatYAML)
+	assert.Nil(t, err, "unexpected error")
+	assert.Empty(t, values, "empty document should be empty")
+
+	_, err = Decode([]byte("[1, 2]"), FormatJSON)
+	assert.NotNil(t, err, "a list is not a dictionary")
+	_, err = Decode([]byte("a: b"), "ini")
+	assert.NotNil(t, err, "unknown format")
+}
+
+func TestDictionaryLowercaseKeys(t *testing.T) {
+	dictionary := NewDictionary()
+	dictionary.LowercaseKeys = true
+	assert.Nil(t, dictionary.AddFile(filepath.Join(rootDir, "test_dictionaries", "test.json")), "unexpected error")
+	assert.Equal(t, "camelCase", dictionary.Values()["accountid"], "key should be lowercase")
+	assert.Nil(t, dictionary.Values()["accountId"], "original key should not exist")
+}
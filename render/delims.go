@@ -0,0 +1,42 @@
+package render
+
+import (
+	"regexp"
+	"text/template"
+)
+
+// Default template delimiters.
+const (
+	DefaultLeftDelim  = "{{"
+	DefaultRightDelim = "}}"
+)
+
+// source is a template file ready to be parsed with its own delimiters.
+type source struct {
+	path    string
+	content []byte
+	left    string
+	right   string
+}
+
+// newSource prepares a template file for parsing. A template can change its delimiters with a header comment
+// written in the delimiters it starts with: {{/* delims "[[" "]]" */}}
+// The header is rewritten as a comment in the new delimiters, so line numbers and trim markers still work.
+func (r *Renderer) newSource(path string, content []byte) source {
+	s := source{path: path, content: content, left: r.options.LeftDelim, right: r.options.RightDelim}
+	pattern := regexp.MustCompile(`^(\s*)` + regexp.QuoteMeta(s.left) + `(-?\s*)/\*\s*delims\s+"([^"]+)"\s+"([^"]+)"\s*\*/(\s*-?)` + regexp.QuoteMeta(s.right))
+	match := pattern.FindSubmatchIndex(content)
+	if match == nil {
+		return s
+	}
+	group := func(i int) string { return string(content[match[2*i]:match[2*i+1]]) }
+	s.left, s.right = group(3), group(4)
+	header := group(1) + s.left + group(2) + `/* delims "` + s.left + `" "` + s.right + `" */` + group(5) + s.right
+	s.content = append([]byte(header), content[match[1]:]...)
+	return s
+}
+
+// parse parses the source into set as a new template named after its path.
+func (s source) parse(set *template.Template) (*template.Template, error) {
+	return set.New(s.path).Delims(s.left, s.right).Parse(string(s.content))
+}
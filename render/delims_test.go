@@ -0,0 +1,40 @@
+package render
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDelims(t *testing.T) {
+	dictionary := map[string]interface{}{"user": "guest"}
+
+	var result bytes.Buffer
+	err := New(Options{Writer: &result, LeftDelim: "[[", RightDelim: "]]"}).
+		RenderReader("stdin", strings.NewReader(`{{ .user }} [[ .user ]] [[ upper "x" ]]`), dictionary)
+	assert.Nil(t, err, "unexpected error")
+	assert.Equal(t, "{{ .user }} guest X", result.String(), "unexpected result")
+}
+
+func TestDelimsHeader(t *testing.T) {
+	var result bytes.Buffer
+	renderer := New(Options{Writer: &result, Strict: true})
+	err := renderer.Render(filepath.Join(rootDir, "test_templates2", "delims.template"), map[string]interface{}{"branch": "main", "missing": ""})
+	assert.Nil(t, err, "unexpected error")
+	assert.Equal(t, "on:\n  push:\n    branches: [ main ]\njobs:\n  build:\n    steps:\n      - run: echo ${{ secrets.TOKEN }} \n", result.String(), "unexpected result")
+
+	// Line numbers are kept
+	err = renderer.Render(filepath.Join(rootDir, "test_templates2", "delims.template"), map[string]interface{}{"branch": "main"})
+	assert.NotNil(t, err, "missing key should fail")
+	assert.Contains(t, err.Error(), "delims.template:8:", "unexpected line number")
+
+	// The header is written in the current delimiters
+	result.Reset()
+	err = New(Options{Writer: &result, LeftDelim: "<%", RightDelim: "%>"}).
+		RenderReader("stdin", strings.NewReader(`<%/* delims "((" "))" */%>(( .user )) <% .user %>`), map[string]interface{}{"user": "guest"})
+	assert.Nil(t, err, "unexpected error")
+	assert.Equal(t, "guest <% .user %>", result.String(), "unexpected result")
+}
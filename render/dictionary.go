@@ -0,0 +1,251 @@
+package render
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// MergeStrategy decides how a list from a file is combined with a list that already exists under the same key.
+type MergeStrategy int
+
+const (
+	// MergeReplace replaces the existing list.
+	MergeReplace MergeStrategy = iota
+	// MergeAppend appends the new items to the existing list.
+	MergeAppend
+)
+
+// ParseMergeStrategy converts "replace" or "append" to a MergeStrategy.
+func ParseMergeStrategy(name string) (MergeStrategy, error) {
+	switch name {
+	case "replace":
+		return MergeReplace, nil
+	case "append":
+		return MergeAppend, nil
+	}
+	return MergeReplace, errors.New(fmt.Sprintf("unknown merge strategy: %s", name))
+}
+
+// Dictionary collects template values from several sources.
+// Values added later take precedence over values added earlier.
+type Dictionary struct {
+	// ListMerge decides how lists are combined when files are merged. Default: MergeReplace
+	ListMerge MergeStrategy
+	// ListSeparator separates the items of lists read by AddLists. Default: DefaultSeparator
+	ListSeparator string
+	// MapPairSeparator separates the key=value pairs of maps read by AddMaps. Default: DefaultSeparator
+	MapPairSeparator string
+	// LowercaseKeys converts the keys read from files to lowercase, like earlier versions of STemplate did.
+	LowercaseKeys bool
+
+	values map[string]interface{}
+}
+
+// NewDictionary returns an empty Dictionary.
+func NewDictionary() *Dictionary {
+	return &Dictionary{values: make(map[string]interface{})}
+}
+
+// Values returns the collected values, ready to be passed to Renderer.Render.
+func (d *Dictionary) Values() map[string]interface{} {
+	return d.values
+}
+
+// Set stores a single value under name.
+func (d *Dictionary) Set(name string, value interface{}) {
+	d.values[name] = value
+}
+
+// AddEnv adds all environment variables as strings.
+func (d *Dictionary) AddEnv() {
+	for _, envVar := range os.Environ() {
+		equals := strings.Index(envVar, "=")
+		if equals < 1 {
+			// Invalid string
+			continue
+		}
+		d.values[envVar[0:equals]] = envVar[equals+1:]
+	}
+}
+
+// AddEnvPrefix adds the environment variables whose name starts with prefix as strings. The prefix is removed.
+// If separator is not empty, the names are converted to lowercase and split into nested keys:
+// with prefix "APP_" and separator "__", APP_DB__HOST becomes db.host. Otherwise the names keep their case.
+func (d *Dictionary) AddEnvPrefix(prefix string, separator string) {
+	environment := os.Environ()
+	// Sorting makes sure that APP_DB__HOST is added after (and replaces) APP_DB
+	sort.Strings(environment)
+	for _, envVar := range environment {
+		equals := strings.Index(envVar, "=")
+		if equals < 1 || !strings.HasPrefix(envVar[0:equals], prefix) {
+			continue
+		}
+		name := envVar[len(prefix):equals]
+		keys := []string{name}
+		if separator != "" {
+			keys = strings.Split(strings.ToLower(name), strings.ToLower(separator))
+		}
+		valid := true
+		for _, key := range keys {
+			valid = valid && key != ""
+		}
+		if !valid {
+			continue
+		}
+		d.setKeys(keys, envVar[equals+1:])
+	}
+}
+
+// AddFile deep-merges the contents of a JSON, YAML or TOML file into the dictionary.
+// Files with an unknown extension are read as TOML.
+// Maps are merged key by key, lists are combined according to ListMerge and everything else is replaced.
+func (d *Dictionary) AddFile(path string) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return d.add(path, data, FormatFromPath(path))
+}
+
+// AddReader deep-merges a JSON, YAML or TOML document read from in, like AddFile. Name is used in error messages.
+func (d *Dictionary) AddReader(name string, in io.Reader, format string) error {
+	data, err := ioutil.ReadAll(in)
+	if err != nil {
+		return err
+	}
+	return d.add(name, data, format)
+}
+
+func (d *Dictionary) add(name string, data []byte, format string) error {
+	values, err := Decode(data, format)
+	if err != nil {
+		return errors.New(fmt.Sprintf("%s: %s", name, err))
+	}
+	if d.LowercaseKeys {
+		values = lowercaseKeys(values).(map[string]interface{})
+	}
+	d.merge(d.values, values)
+	return nil
+}
+
+// AddJSON adds the named environment variables that contain JSON values.
+func (d *Dictionary) AddJSON(names []string) error {
+	return d.addEncoded(names, FormatJSON)
+}
+
+// AddYAML adds the named environment variables that contain YAML values.
+func (d *Dictionary) AddYAML(names []string) error {
+	return d.addEncoded(names, FormatYAML)
+}
+
+func (d *Dictionary) addEncoded(names []string, format string) error {
+	for _, envVar := range names {
+		value, err := DecodeValue([]byte(os.Getenv(envVar)), format)
+		if err != nil {
+			return errors.New(fmt.Sprintf("%s does not contain %s: %s", envVar, format, err))
+		}
+		d.values[envVar] = value
+	}
+	return nil
+}
+
+// merge deep-merges src into dst.
+func (d *Dictionary) merge(dst map[string]interface{}, src map[string]interface{}) {
+	for k, value := range src {
+		dstMap, dstIsMap := dst[k].(map[string]interface{})
+		srcMap, srcIsMap := value.(map[string]interface{})
+		if dstIsMap && srcIsMap {
+			d.merge(dstMap, srcMap)
+			continue
+		}
+		if d.ListMerge == MergeAppend {
+			if list, ok := appendLists(dst[k], value); ok {
+				dst[k] = list
+				continue
+			}
+		}
+		dst[k] = value
+	}
+}
+
+// appendLists returns the items of a followed by the items of b, if both are slices.
+func appendLists(a interface{}, b interface{}) ([]interface{}, bool) {
+	av := reflect.ValueOf(a)
+	bv := reflect.ValueOf(b)
+	if av.Kind() != reflect.Slice || bv.Kind() != reflect.Slice {
+		return nil, false
+	}
+	result := make([]interface{}, 0, av.Len()+bv.Len())
+	for i := 0; i < av.Len(); i++ {
+		result = append(result, av.Index(i).Interface())
+	}
+	for i := 0; i < bv.Len(); i++ {
+		result = append(result, bv.Index(i).Interface())
+	}
+	return result, true
+}
+
+// AddStrings adds the named environment variables as strings.
+func (d *Dictionary) AddStrings(names []string) {
+	for _, envVar := range names {
+		d.values[envVar] = os.Getenv(envVar)
+	}
+}
+
+// AddLists adds the named environment variables as lists of strings separated by ListSeparator.
+// Items can be quoted and separators can be escaped with a backslash. An empty variable is an empty list.
+func (d *Dictionary) AddLists(names []string) error {
+	for _, envVar := range names {
+		items, err := splitEscaped(os.Getenv(envVar), d.listSeparator(), false)
+		if err != nil {
+			return errors.New(fmt.Sprintf("%s does not contain a list: %s", envVar, err))
+		}
+		for i, item := range items {
+			items[i] = unquote(item)
+		}
+		d.values[envVar] = items
+	}
+	return nil
+}
+
+// AddMaps adds the named environment variables as lists of key=value pairs separated by MapPairSeparator.
+// Keys and values can be quoted and separators can be escaped with a backslash. An empty variable is an empty map.
+func (d *Dictionary) AddMaps(names []string) error {
+	for _, envVar := range names {
+		pairs, err := splitEscaped(os.Getenv(envVar), d.mapPairSeparator(), true)
+		if err != nil {
+			return errors.New(fmt.Sprintf("%s does not contain a map: %s", envVar, err))
+		}
+		tempMap := make(map[string]string)
+		for _, mapItem := range pairs {
+			m, _ := splitEscaped(mapItem, "=", false)
+			if len(m) < 2 {
+				// something's not right, there's no equal sign (=) in the variable
+				return errors.New(fmt.Sprintf("Missing =. %s does not contain a map: %s", envVar, mapItem))
+			}
+			tempMap[unquote(m[0])] = unquote(strings.Join(m[1:], "="))
+		}
+		d.values[envVar] = tempMap
+	}
+	return nil
+}
+
+func (d *Dictionary) listSeparator() string {
+	if d.ListSeparator == "" {
+		return DefaultSeparator
+	}
+	return d.ListSeparator
+}
+
+func (d *Dictionary) mapPairSeparator() string {
+	if d.MapPairSeparator == "" {
+		return DefaultSeparator
+	}
+	return d.MapPairSeparator
+}
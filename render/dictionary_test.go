@@ -0,0 +1,91 @@
+package render
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDictionaryPrecedence(t *testing.T) {
+	_ = os.Setenv("user", "envuser")
+	_ = os.Setenv("filename", "envfile")
+	_ = os.Setenv("list", "first,second")
+	_ = os.Setenv("map", "test=envmap,nottest=a=b")
+
+	dictionary := NewDictionary()
+	dictionary.AddEnv()
+	assert.Equal(t, "envuser", dictionary.Values()["user"], "unexpected env value")
+
+	assert.Nil(t, dictionary.AddFile(filepath.Join(rootDir, "test_dictionaries", "test.json")), "unexpected error")
+	assert.Equal(t, "guest", dictionary.Values()["user"], "file should override env")
+
+	dictionary.AddStrings([]string{"filename"})
+	assert.Equal(t, "envfile", dictionary.Values()["filename"], "string should override file")
+
+	assert.Nil(t, dictionary.AddLists([]string{"list"}), "unexpected error")
+	assert.Equal(t, []string{"first", "second"}, dictionary.Values()["list"], "list should override file")
+
+	assert.Nil(t, dictionary.AddMaps([]string{"map"}), "unexpected error")
+	assert.Equal(t, map[string]string{"test": "envmap", "nottest": "a=b"}, dictionary.Values()["map"], "map should override file")
+
+	_ = os.Setenv("map", "broken")
+	assert.NotNil(t, dictionary.AddMaps([]string{"map"}), "missing = should fail")
+}
+
+func TestDictionaryMerge(t *testing.T) {
+	dictionary := NewDictionary()
+	assert.Nil(t, dictionary.AddFile(filepath.Join(rootDir, "test_dictionaries", "test.yaml")), "unexpected error")
+	assert.Nil(t, dictionary.AddFile(filepath.Join(rootDir, "test_dictionaries", "override.yaml")), "unexpected error")
+	values := dictionary.Values()
+	assert.Equal(t, "admin", values["user"], "scalar should be replaced")
+	assert.Equal(t, "test", values["filename"], "scalar should be kept")
+	assert.Equal(t, []interface{}{"fourth"}, values["list"], "list should be replaced")
+	assert.Equal(t, map[string]interface{}{"test": "overridden", "nottest": "not a test map", "extra": "added by override"}, values["map"], "map should be merged")
+
+	dictionary = NewDictionary()
+	dictionary.ListMerge = MergeAppend
+	assert.Nil(t, dictionary.AddFile(filepath.Join(rootDir, "test_dictionaries", "test.json")), "unexpected error")
+	assert.Nil(t, dictionary.AddFile(filepath.Join(rootDir, "test_dictionaries", "override.yaml")), "unexpected error")
+	assert.Equal(t, []interface{}{"first", "second", "third", "fourth"}, dictionary.Values()["list"], "list should be appended")
+
+	_, err := ParseMergeStrategy("prepend")
+	assert.NotNil(t, err, "unknown strategy should fail")
+}
+
+func TestDictionaryEnvPrefix(t *testing.T) {
+	_ = os.Setenv("STEMPLATE_TEST_NAME", "app")
+	_ = os.Setenv("STEMPLATE_TEST_DB", "replaced")
+	_ = os.Setenv("STEMPLATE_TEST_DB__HOST", "localhost")
+	_ = os.Setenv("STEMPLATE_TEST_DB__PORT", "5432")
+	_ = os.Setenv("STEMPLATE_TEST_BROKEN__", "skipped")
+
+	dictionary := NewDictionary()
+	dictionary.AddEnvPrefix("STEMPLATE_TEST_", "__")
+	assert.Equal(t, map[string]interface{}{
+		"name": "app",
+		"db":   map[string]interface{}{"host": "localhost", "port": "5432"},
+	}, dictionary.Values(), "unexpected result")
+
+	dictionary = NewDictionary()
+	dictionary.AddEnvPrefix("STEMPLATE_TEST_", "")
+	assert.Equal(t, "localhost", dictionary.Values()["DB__HOST"], "names should not be split or lowercased without separator")
+	assert.Nil(t, dictionary.Values()["user"], "variables without prefix should not be added")
+}
+
+func TestDictionaryEncoded(t *testing.T) {
+	_ = os.Setenv("jsontest", `{"servers": [{"host": "a", "port": 80, "tls": true}]}`)
+	_ = os.Setenv("yamltest", "- first\n- second: 2\n")
+	_ = os.Setenv("brokentest", `{"servers": `)
+
+	dictionary := NewDictionary()
+	assert.Nil(t, dictionary.AddJSON([]string{"jsontest"}), "unexpected error")
+	assert.Nil(t, dictionary.AddYAML([]string{"yamltest"}), "unexpected error")
+	assert.Equal(t, map[string]interface{}{
+		"servers": []interface{}{map[string]interface{}{"host": "a", "port": float64(80), "tls": true}},
+	}, dictionary.Values()["jsontest"], "unexpected JSON")
+	assert.Equal(t, []interface{}{"first", map[string]interface{}{"second": 2}}, dictionary.Values()["yamltest"], "unexpected YAML")
+
+	assert.NotNil(t, dictionary.AddJSON([]string{"brokentest"}), "invalid JSON should fail")
+}
@@ -0,0 +1,214 @@
+package render
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+)
+
+// AddDotenv adds the variables of a dotenv file as strings.
+// The file contains KEY=value lines, optionally prefixed with "export". Comments start with #.
+// Values in single quotes are taken literally. Unquoted values and values in double quotes expand ${VAR} and $VAR
+// with the variables defined earlier in the file or in the environment. Double quotes also support \n, \t, \", \\ and \$ escapes.
+func (d *Dictionary) AddDotenv(path string) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	values, err := parseDotenv(string(data))
+	if err != nil {
+		return errors.New(fmt.Sprintf("%s: %s", path, err))
+	}
+	for k, v := range values {
+		d.values[k] = v
+	}
+	return nil
+}
+
+// dotenvParser holds the state of parsing a dotenv document.
+type dotenvParser struct {
+	data   string
+	pos    int
+	values map[string]string
+}
+
+func parseDotenv(data string) (map[string]string, error) {
+	p := &dotenvParser{data: data, values: make(map[string]string)}
+	for {
+		p.skip(" \t\r\n")
+		if p.pos >= len(p.data) {
+			return p.values, nil
+		}
+		if p.data[p.pos] == '#' {
+			p.skipLine()
+			continue
+		}
+		if err := p.parseLine(); err != nil {
+			return nil, errors.New(fmt.Sprintf("line %d: %s", p.line(), err))
+		}
+	}
+}
+
+func (p *dotenvParser) parseLine() error {
+	if strings.HasPrefix(p.data[p.pos:], "export ") || strings.HasPrefix(p.data[p.pos:], "export\t") {
+		p.pos += len("export")
+		p.skip(" \t")
+	}
+	start := p.pos
+	for p.pos < len(p.data) && isDotenvKeyChar(p.data[p.pos]) {
+		p.pos++
+	}
+	key := p.data[start:p.pos]
+	if key == "" {
+		return errors.New("missing variable name")
+	}
+	p.skip(" \t")
+	if p.pos >= len(p.data) || p.data[p.pos] != '=' {
+		return errors.New(fmt.Sprintf("Missing =. %s is not a key=value pair", key))
+	}
+	p.pos++
+	p.skip(" \t")
+
+	var value string
+	var err error
+	switch {
+	case p.pos < len(p.data) && p.data[p.pos] == '\'':
+		value, err = p.singleQuoted()
+	case p.pos < len(p.data) && p.data[p.pos] == '"':
+		value, err = p.doubleQuoted()
+	default:
+		value, err = p.unquoted()
+	}
+	if err != nil {
+		return err
+	}
+
+	// Only a comment can follow the value
+	p.skip(" \t\r")
+	if p.pos < len(p.data) && p.data[p.pos] != '\n' && p.data[p.pos] != '#' {
+		return errors.New(fmt.Sprintf("unexpected characters after the value of %s", key))
+	}
+	p.skipLine()
+	p.values[key] = value
+	return nil
+}
+
+func (p *dotenvParser) singleQuoted() (string, error) {
+	end := strings.IndexByte(p.data[p.pos+1:], '\'')
+	if end < 0 {
+		return "", errors.New("unterminated single quote")
+	}
+	value := p.data[p.pos+1 : p.pos+1+end]
+	p.pos += end + 2
+	return value, nil
+}
+
+func (p *dotenvParser) doubleQuoted() (string, error) {
+	var value strings.Builder
+	p.pos++
+	for p.pos < len(p.data) {
+		c := p.data[p.pos]
+		switch {
+		case c == '"':
+			p.pos++
+			return value.String(), nil
+		case c == '\\' && p.pos+1 < len(p.data):
+			switch p.data[p.pos+1] {
+			case 'n':
+				value.WriteByte('\n')
+			case 'r':
+				value.WriteByte('\r')
+			case 't':
+				value.WriteByte('\t')
+			case '"', '\\', '$':
+				value.WriteByte(p.data[p.pos+1])
+			default:
+				value.WriteByte('\\')
+				value.WriteByte(p.data[p.pos+1])
+			}
+			p.pos += 2
+		case c == '$':
+			expanded, err := p.expand("\"\n")
+			if err != nil {
+				return "", err
+			}
+			value.WriteString(expanded)
+		default:
+			value.WriteByte(c)
+			p.pos++
+		}
+	}
+	return "", errors.New("unterminated double quote")
+}
+
+func (p *dotenvParser) unquoted() (string, error) {
+	var value strings.Builder
+	for p.pos < len(p.data) && p.data[p.pos] != '\n' {
+		c := p.data[p.pos]
+		// A # after whitespace starts a comment
+		if c == '#' && (value.Len() == 0 || strings.ContainsRune(" \t", rune(p.data[p.pos-1]))) {
+			break
+		}
+		if c == '$' {
+			expanded, err := p.expand("\n")
+			if err != nil {
+				return "", err
+			}
+			value.WriteString(expanded)
+			continue
+		}
+		value.WriteByte(c)
+		p.pos++
+	}
+	return strings.TrimRight(value.String(), " \t\r"), nil
+}
+
+// expand replaces the ${VAR} or $VAR reference at the current position with its value.
+// The closing brace of ${VAR} has to come before any of the stop characters.
+func (p *dotenvParser) expand(stop string) (string, error) {
+	p.pos++ // $
+	var name string
+	if p.pos < len(p.data) && p.data[p.pos] == '{' {
+		end := strings.IndexAny(p.data[p.pos:], "}"+stop)
+		if end < 0 || p.data[p.pos+end] != '}' {
+			return "", errors.New("unterminated ${")
+		}
+		name = p.data[p.pos+1 : p.pos+end]
+		p.pos += end + 1
+	} else {
+		start := p.pos
+		for p.pos < len(p.data) && isDotenvKeyChar(p.data[p.pos]) && p.data[p.pos] != '.' && p.data[p.pos] != '-' {
+			p.pos++
+		}
+		name = p.data[start:p.pos]
+		if name == "" {
+			return "$", nil
+		}
+	}
+	if value, ok := p.values[name]; ok {
+		return value, nil
+	}
+	return os.Getenv(name), nil
+}
+
+func (p *dotenvParser) skip(chars string) {
+	for p.pos < len(p.data) && strings.IndexByte(chars, p.data[p.pos]) >= 0 {
+		p.pos++
+	}
+}
+
+func (p *dotenvParser) skipLine() {
+	for p.pos < len(p.data) && p.data[p.pos] != '\n' {
+		p.pos++
+	}
+}
+
+func (p *dotenvParser) line() int {
+	return strings.Count(p.data[:p.pos], "\n") + 1
+}
+
+func isDotenvKeyChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '.' || c == '-'
+}
@@ -0,0 +1,46 @@
+package render
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddDotenv(t *testing.T) {
+	_ = os.Setenv("HOME_TEST", "/home/guest")
+	dictionary := NewDictionary()
+	assert.Nil(t, dictionary.AddDotenv(filepath.Join(rootDir, "test_dictionaries", "test.env")), "unexpected error")
+
+	values := dictionary.Values()
+	assert.Equal(t, "guest", values["user"], "export prefix")
+	assert.Equal(t, "dotenv", values["filename"], "inline comment")
+	assert.Equal(t, "Hi guest!\nWelcome.", values["greeting"], "double quotes")
+	assert.Equal(t, "no $expansion here # or comment", values["literal"], "single quotes")
+	assert.Equal(t, "/home/guest/bin", values["path"], "environment expansion")
+	assert.Equal(t, "color#fff", values["hash"], "# inside a value")
+	assert.Equal(t, "first\nsecond", values["multiline"], "multiline value")
+	assert.Equal(t, "", values["empty"], "empty value")
+}
+
+func TestParseDotenvErrors(t *testing.T) {
+	for _, data := range []string{
+		"novalue\n",
+		"=value\n",
+		"quote=\"unterminated\n",
+		"quote='unterminated\n",
+		"quote=\"value\" trailing\n",
+		"A=${B\nC=}\nD=1\n",
+		"A=x${B\n",
+		"A=\"${B\" }\"\n",
+	} {
+		_, err := parseDotenv(data)
+		assert.NotNil(t, err, "expected error for "+data)
+	}
+
+	_, err := parseDotenv("a=1\n\nb\n")
+	assert.Contains(t, err.Error(), "line 3", "error should contain the line number")
+	_, err = parseDotenv("a=1\nb=${c\nd=}\n")
+	assert.Contains(t, err.Error(), "line 2", "error should point to the unterminated reference")
+}
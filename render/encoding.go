@@ -0,0 +1,78 @@
+package render
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+func sha256sum(s string) string {
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(s)))
+}
+
+func sha1sum(s string) string {
+	return fmt.Sprintf("%x", sha1.Sum([]byte(s)))
+}
+
+func md5sum(s string) string {
+	return fmt.Sprintf("%x", md5.Sum([]byte(s)))
+}
+
+// crc32sum returns the IEEE CRC-32 checksum of s as 8 hexadecimal digits.
+func crc32sum(s string) string {
+	return fmt.Sprintf("%08x", crc32.ChecksumIEEE([]byte(s)))
+}
+
+func b64enc(s string) string {
+	return base64.StdEncoding.EncodeToString([]byte(s))
+}
+
+func b64dec(s string) (string, error) {
+	result, err := base64.StdEncoding.DecodeString(s)
+	return string(result), err
+}
+
+func b32enc(s string) string {
+	return base32.StdEncoding.EncodeToString([]byte(s))
+}
+
+func b32dec(s string) (string, error) {
+	result, err := base32.StdEncoding.DecodeString(s)
+	return string(result), err
+}
+
+func hexenc(s string) string {
+	return hex.EncodeToString([]byte(s))
+}
+
+func hexdec(s string) (string, error) {
+	result, err := hex.DecodeString(s)
+	return string(result), err
+}
+
+// fileSha256 returns a function that hashes a file. Relative paths are relative to the directory of templatePath.
+func fileSha256(templatePath string) func(string) (string, error) {
+	return func(path string) (string, error) {
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(filepath.Dir(templatePath), path)
+		}
+		file, err := os.Open(path)
+		if err != nil {
+			return "", err
+		}
+		defer file.Close()
+		hash := sha256.New()
+		if _, err = io.Copy(hash, file); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%x", hash.Sum(nil)), nil
+	}
+}
@@ -0,0 +1,47 @@
+package render
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHashes(t *testing.T) {
+	assert.Equal(t, "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824", sha256sum("hello"), "unexpected sha256")
+	assert.Equal(t, "aaf4c61ddcc5e8a2dabede0f3b482cd9aea9434d", sha1sum("hello"), "unexpected sha1")
+	assert.Equal(t, "5d41402abc4b2a76b9719d911017c592", md5sum("hello"), "unexpected md5")
+	assert.Equal(t, "3610a686", crc32sum("hello"), "unexpected crc32")
+}
+
+func TestEncodings(t *testing.T) {
+	assert.Equal(t, "aGVsbG8=", b64enc("hello"), "unexpected base64")
+	assert.Equal(t, "NBSWY3DP", b32enc("hello"), "unexpected base32")
+	assert.Equal(t, "68656c6c6f", hexenc("hello"), "unexpected hex")
+
+	for encoded, decode := range map[string]func(string) (string, error){
+		"aGVsbG8=":   b64dec,
+		"NBSWY3DP":   b32dec,
+		"68656c6c6f": hexdec,
+	} {
+		result, err := decode(encoded)
+		assert.Nil(t, err, "unexpected error")
+		assert.Equal(t, "hello", result, "unexpected result")
+		_, err = decode("!" + encoded)
+		assert.NotNil(t, err, "invalid input should fail")
+	}
+}
+
+func TestFileSha256(t *testing.T) {
+	// The path is relative to the template
+	var result bytes.Buffer
+	err := New(Options{Writer: &result}).Render(filepath.Join(rootDir, "test_templates2", "checksum.template"), nil)
+	assert.Nil(t, err, "unexpected error")
+	expected, err := fileSha256(".")(filepath.Join(rootDir, "test_templates", "test.template"))
+	assert.Nil(t, err, "unexpected error")
+	assert.Equal(t, "checksum: "+expected+"\n", result.String(), "unexpected result")
+
+	_, err = fileSha256(".")("nonexistent")
+	assert.NotNil(t, err, "missing file should fail")
+}
@@ -0,0 +1,68 @@
+package render
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+)
+
+// extends returns the name of the base template declared at the beginning of the source with an
+// {{/* extends "base.template" */}} comment, or "". The comment can follow a delims header.
+func (s source) extends() string {
+	left := regexp.QuoteMeta(s.left) + `-?\s*/\*\s*`
+	right := `\s*\*/\s*-?` + regexp.QuoteMeta(s.right)
+	pattern := regexp.MustCompile(`^\s*(` + left + `delims\s[^*]*` + right + `\s*)?` + left + `extends\s+"([^"]+)"` + right)
+	match := pattern.FindSubmatch(s.content)
+	if match == nil {
+		return ""
+	}
+	return string(match[2])
+}
+
+// layouts follows the extends comments from a template to its root base template.
+// The result starts with the template itself and ends with the root.
+func (r *Renderer) layouts(path string, content []byte) ([]source, error) {
+	chain := []source{r.newSource(path, content)}
+	seen := map[string]bool{filepath.Clean(path): true}
+	for {
+		current := chain[len(chain)-1]
+		name := current.extends()
+		if name == "" {
+			return chain, nil
+		}
+		basePath, err := r.resolveBase(current.path, name)
+		if err != nil {
+			return nil, err
+		}
+		if seen[filepath.Clean(basePath)] {
+			return nil, errors.New(fmt.Sprintf("%s: extends loop through %s", path, basePath))
+		}
+		seen[filepath.Clean(basePath)] = true
+		baseContent, err := ioutil.ReadFile(basePath)
+		if err != nil {
+			return nil, err
+		}
+		chain = append(chain, r.newSource(basePath, baseContent))
+	}
+}
+
+// resolveBase finds the file of a base template. The name is relative to the directory of the extending template
+// or to one of the partials directories.
+func (r *Renderer) resolveBase(from string, name string) (string, error) {
+	candidates := []string{filepath.Join(filepath.Dir(from), name)}
+	if filepath.IsAbs(name) {
+		candidates = []string{name}
+	}
+	for _, dir := range r.options.Partials {
+		candidates = append(candidates, filepath.Join(dir, name))
+	}
+	for _, candidate := range candidates {
+		if info, err := os.Stat(candidate); err == nil && !info.IsDir() {
+			return candidate, nil
+		}
+	}
+	return "", errors.New(fmt.Sprintf("%s: base template %q not found", from, name))
+}
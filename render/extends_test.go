@@ -0,0 +1,60 @@
+package render
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseExtends(t *testing.T) {
+	r := New(Options{})
+	assert.Equal(t, "base.template", r.newSource("t", []byte(`{{/* extends "base.template" */}}`)).extends(), "unexpected base")
+	assert.Equal(t, "base.template", r.newSource("t", []byte("\n{{- /* extends \"base.template\" */ -}}\n")).extends(), "unexpected base")
+	assert.Equal(t, "", r.newSource("t", []byte("text\n{{/* extends \"base.template\" */}}")).extends(), "extends must be at the beginning")
+	assert.Equal(t, "", r.newSource("t", []byte(`{{/* a comment */}}`)).extends(), "unexpected base")
+	assert.Equal(t, "base.template", r.newSource("t", []byte("{{/* delims \"[[\" \"]]\" */}}\n[[/* extends \"base.template\" */]]")).extends(), "extends after delims")
+}
+
+func TestExtends(t *testing.T) {
+	dictionary := map[string]interface{}{"user": "backend", "filename": "example.com"}
+
+	var result bytes.Buffer
+	err := New(Options{Writer: &result}).Render(filepath.Join(rootDir, "test_templates2", "service.conf.template"), dictionary)
+	assert.Nil(t, err, "unexpected error")
+	assert.Equal(t, "server {\n    listen 80;\n    server_name example.com;\n\n    location / {\n        proxy_pass http://backend;\n    }\n}\n", result.String(), "unexpected result")
+
+	// Two levels of inheritance
+	result.Reset()
+	err = New(Options{Writer: &result}).Render(filepath.Join(rootDir, "test_templates2", "tls.conf.template"), dictionary)
+	assert.Nil(t, err, "unexpected error")
+	assert.Contains(t, result.String(), "listen 443 ssl;", "unexpected result")
+	assert.Contains(t, result.String(), "proxy_pass http://backend;", "unexpected result")
+
+	// Base templates are found in the partials too
+	result.Reset()
+	err = New(Options{Writer: &result, Partials: []string{filepath.Join(rootDir, "test_templates2")}}).
+		RenderReader("stdin", strings.NewReader(`{{/* extends "base.conf.template" */}}{{ define "listen" }}8080{{ end }}`), dictionary)
+	assert.Nil(t, err, "unexpected error")
+	assert.Contains(t, result.String(), "listen 8080;", "unexpected result")
+	assert.Contains(t, result.String(), "return 404;", "unexpected result")
+
+	err = New(Options{Writer: &result}).RenderReader("stdin", strings.NewReader(`{{/* extends "nonexistent.template" */}}`), dictionary)
+	assert.NotNil(t, err, "missing base should fail")
+}
+
+func TestExtendsLoop(t *testing.T) {
+	dir, err := ioutil.TempDir("", "stemplate")
+	assert.Nil(t, err, "unexpected error")
+	defer os.RemoveAll(dir)
+	_ = ioutil.WriteFile(filepath.Join(dir, "a.template"), []byte(`{{/* extends "b.template" */}}`), 0644)
+	_ = ioutil.WriteFile(filepath.Join(dir, "b.template"), []byte(`{{/* extends "a.template" */}}`), 0644)
+
+	err = New(Options{Writer: ioutil.Discard}).Render(filepath.Join(dir, "a.template"), nil)
+	assert.NotNil(t, err, "loop should fail")
+	assert.Contains(t, err.Error(), "extends loop", "unexpected error")
+}
@@ -0,0 +1,139 @@
+package render
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+// funcMap returns the built-in template functions bound to dictionary and configured by options.
+func funcMap(dictionary map[string]interface{}, options Options) template.FuncMap {
+	slicer := runeSlicer{clamp: options.ClampStrings}
+	return template.FuncMap{
+		"substitute": func(name string) (interface{}, error) {
+			value, ok := dictionary[name]
+			if !ok && options.Strict {
+				return nil, errors.New(fmt.Sprintf("map has no entry for key \"%s\"", name))
+			}
+			return value, nil
+		},
+		"lookup":    lookupFunc(dictionary, options.Strict),
+		"get":       getFunc(options.Strict),
+		"counter":   counter,
+		"seq":       seq,
+		"until":     until,
+		"untilStep": untilStep,
+		// Character slicing
+		"left":     slicer.left,
+		"right":    slicer.right,
+		"mid":      slicer.mid,
+		"substr":   slicer.substr,
+		"truncate": truncate,
+		"runelen":  runelen,
+		// String library
+		"upper":      strings.ToUpper,
+		"lower":      strings.ToLower,
+		"title":      strings.Title,
+		"trim":       trim,
+		"trimPrefix": trimPrefix,
+		"trimSuffix": trimSuffix,
+		"replace":    replace,
+		"split":      split,
+		"join":       join,
+		"contains":   contains,
+		"hasPrefix":  hasPrefix,
+		"hasSuffix":  hasSuffix,
+		"repeat":     repeat,
+		"indent":     indent,
+		"nindent":    nindent,
+		// Serialization
+		"toJson":       toJSON,
+		"toPrettyJson": toPrettyJSON,
+		"toYaml":       toYAML,
+		"toToml":       toTOML,
+		"fromJson":     fromJSON,
+		"fromYaml":     fromYAML,
+		"fromToml":     fromTOML,
+		// Defaults
+		"default":  defaultValue,
+		"empty":    empty,
+		"coalesce": coalesce,
+		"ternary":  ternary,
+		"required": required,
+		// Hashing and encoding
+		"sha256sum": sha256sum,
+		"sha1sum":   sha1sum,
+		"md5sum":    md5sum,
+		"crc32":     crc32sum,
+		"b64enc":    b64enc,
+		"b64dec":    b64dec,
+		"b32enc":    b32enc,
+		"b32dec":    b32dec,
+		"hexenc":    hexenc,
+		"hexdec":    hexdec,
+		// Date and time
+		"now":        nowFunc(options.Now),
+		"date":       date,
+		"dateModify": dateModify,
+		"unixEpoch":  unixEpoch,
+		"toDate":     toDate,
+		// Regular expressions
+		"regexMatch":      regexMatch,
+		"regexFind":       regexFind,
+		"regexFindAll":    regexFindAll,
+		"regexReplaceAll": regexReplaceAll,
+		"regexSplit":      regexSplit,
+		"regexCapture":    regexCapture,
+		// Lists and maps
+		"list":      list,
+		"dict":      dict,
+		"append":    appendItem,
+		"prepend":   prependItem,
+		"concat":    concat,
+		"uniq":      uniq,
+		"sortAlpha": sortAlpha,
+		"sortBy":    sortBy,
+		"reverse":   reverse,
+		"first":     first,
+		"last":      last,
+		"rest":      rest,
+		"slice":     slice,
+		"keys":      keys,
+		"values":    values,
+		"pick":      pick,
+		"omit":      omit,
+		"hasKey":    hasKey,
+		"merge":     merge,
+		"set":       set,
+		// Paths
+		"base":     filepath.Base,
+		"dir":      filepath.Dir,
+		"ext":      filepath.Ext,
+		"clean":    filepath.Clean,
+		"joinPath": joinPath,
+		"relPath":  relPath,
+		// Arithmetic
+		"add":   add,
+		"sub":   sub,
+		"mul":   mul,
+		"div":   div,
+		"mod":   mod,
+		"min":   min,
+		"max":   max,
+		"floor": floor,
+		"ceil":  ceil,
+		"round": round,
+	}
+}
+
+// fileFuncs returns the template functions that depend on the template set, the path of the template being executed
+// and the path of its result file.
+func fileFuncs(set *template.Template, path string, output string) template.FuncMap {
+	return template.FuncMap{
+		"include":    includeFunc(set),
+		"fileSha256": fileSha256(path),
+		"stemplate":  contextFunc(path, output),
+	}
+}
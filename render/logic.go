@@ -0,0 +1,65 @@
+package render
+
+import (
+	"errors"
+	"reflect"
+)
+
+// empty checks if value is nil, false, zero, or an empty string, list or map.
+func empty(value interface{}) bool {
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Invalid:
+		return true
+	case reflect.String, reflect.Slice, reflect.Array, reflect.Map:
+		return v.Len() == 0
+	case reflect.Ptr, reflect.Interface:
+		return v.IsNil()
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	}
+	return false
+}
+
+// defaultValue returns value, or fallback if value is empty. Use it in a pipeline: {{ .port | default 8080 }}
+func defaultValue(fallback interface{}, value interface{}) interface{} {
+	if empty(value) {
+		return fallback
+	}
+	return value
+}
+
+// coalesce returns the first value that is not empty, or nil.
+func coalesce(values ...interface{}) interface{} {
+	for _, value := range values {
+		if !empty(value) {
+			return value
+		}
+	}
+	return nil
+}
+
+// ternary returns trueValue if condition is true, falseValue otherwise.
+func ternary(trueValue interface{}, falseValue interface{}, condition bool) interface{} {
+	if condition {
+		return trueValue
+	}
+	return falseValue
+}
+
+// required returns value, or fails the rendering with message if value is missing or an empty string.
+func required(message string, value interface{}) (interface{}, error) {
+	if value == nil {
+		return nil, errors.New(message)
+	}
+	if s, ok := value.(string); ok && s == "" {
+		return nil, errors.New(message)
+	}
+	return value, nil
+}
@@ -0,0 +1,53 @@
+package render
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEmpty(t *testing.T) {
+	for _, value := range []interface{}{nil, "", 0, int64(0), 0.0, false, []string{}, map[string]string{}, []interface{}{}} {
+		assert.True(t, empty(value), "expected empty")
+	}
+	for _, value := range []interface{}{"a", 1, -1.5, true, []string{""}, map[string]int{"a": 0}} {
+		assert.False(t, empty(value), "expected not empty")
+	}
+}
+
+func TestDefaults(t *testing.T) {
+	assert.Equal(t, "fallback", defaultValue("fallback", nil), "unexpected default")
+	assert.Equal(t, "fallback", defaultValue("fallback", ""), "unexpected default")
+	assert.Equal(t, "value", defaultValue("fallback", "value"), "unexpected default")
+	assert.Equal(t, 8080, defaultValue(8080, 0), "unexpected default")
+
+	assert.Equal(t, "b", coalesce(nil, "", "b", "c"), "unexpected coalesce")
+	assert.Nil(t, coalesce(nil, ""), "unexpected coalesce")
+
+	assert.Equal(t, "yes", ternary("yes", "no", true), "unexpected ternary")
+	assert.Equal(t, "no", ternary("yes", "no", false), "unexpected ternary")
+
+	value, err := required("value is required", 0)
+	assert.Nil(t, err, "zero is a value")
+	assert.Equal(t, 0, value, "unexpected required")
+	_, err = required("value is required", "")
+	assert.EqualError(t, err, "value is required", "unexpected error")
+	_, err = required("value is required", nil)
+	assert.EqualError(t, err, "value is required", "unexpected error")
+}
+
+func TestRenderDefaults(t *testing.T) {
+	dictionary := map[string]interface{}{"user": "guest", "port": "", "debug": true}
+	template := `{{ .port | default 8080 }} {{ .missing | default "none" }} {{ coalesce .missing .user }} {{ ternary "on" "off" .debug }} {{ empty .port }}`
+
+	var result bytes.Buffer
+	err := New(Options{Writer: &result}).RenderReader("defaults", strings.NewReader(template), dictionary)
+	assert.Nil(t, err, "unexpected error")
+	assert.Equal(t, "8080 none guest on true", result.String(), "unexpected result")
+
+	err = New(Options{Writer: &result}).RenderReader("defaults", strings.NewReader(`{{ required "the database password is required" .password }}`), dictionary)
+	assert.NotNil(t, err, "required should fail")
+	assert.Contains(t, err.Error(), "the database password is required", "error should contain the message")
+}
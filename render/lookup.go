@@ -0,0 +1,117 @@
+package render
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// pathStep is a map key or a list index in a lookup path.
+type pathStep struct {
+	key     string
+	index   int
+	isIndex bool
+}
+
+// parseLookupPath splits a path like "a.b[2].c" into map keys and list indices.
+// Use "\." for a dot that is part of a key.
+func parseLookupPath(path string) ([]pathStep, error) {
+	var steps []pathStep
+	for i, part := range splitPath(path) {
+		bracket := strings.IndexByte(part, '[')
+		if bracket < 0 {
+			bracket = len(part)
+		}
+		key := part[:bracket]
+		if key != "" {
+			steps = append(steps, pathStep{key: key})
+		} else if i > 0 || bracket == len(part) {
+			// Only the first part can start with an index: get $list "[0].name"
+			return nil, errors.New(fmt.Sprintf("invalid key path: %s", path))
+		}
+		for rest := part[bracket:]; rest != ""; {
+			end := strings.IndexByte(rest, ']')
+			if rest[0] != '[' || end < 0 {
+				return nil, errors.New(fmt.Sprintf("invalid key path: %s", path))
+			}
+			index, err := strconv.Atoi(rest[1:end])
+			if err != nil || index < 0 {
+				return nil, errors.New(fmt.Sprintf("invalid index in key path: %s", path))
+			}
+			steps = append(steps, pathStep{index: index, isIndex: true})
+			rest = rest[end+1:]
+		}
+	}
+	return steps, nil
+}
+
+// resolvePath returns the value at path in input, and false if it does not exist.
+// Maps of any key type and lists of any item type are supported.
+func resolvePath(input interface{}, steps []pathStep) (interface{}, bool) {
+	current := input
+	for _, step := range steps {
+		value := reflect.ValueOf(current)
+		var next reflect.Value
+		switch {
+		case step.isIndex && (value.Kind() == reflect.Slice || value.Kind() == reflect.Array):
+			if step.index >= value.Len() {
+				return nil, false
+			}
+			next = value.Index(step.index)
+		case !step.isIndex && value.Kind() == reflect.Map:
+			if value.Type().Key().Kind() == reflect.String {
+				next = value.MapIndex(reflect.ValueOf(step.key).Convert(value.Type().Key()))
+				break
+			}
+			for _, key := range value.MapKeys() {
+				if fmt.Sprint(key.Interface()) == step.key {
+					next = value.MapIndex(key)
+					break
+				}
+			}
+		}
+		if !next.IsValid() {
+			return nil, false
+		}
+		current = next.Interface()
+	}
+	return current, true
+}
+
+// getPath returns the value at path in input. If it does not exist, getPath returns the fallback if one is given,
+// fails if strict is set, and returns nil otherwise.
+func getPath(input interface{}, path string, strict bool, fallback []interface{}) (interface{}, error) {
+	if len(fallback) > 1 {
+		return nil, errors.New("only one default value can be given")
+	}
+	steps, err := parseLookupPath(path)
+	if err != nil {
+		return nil, err
+	}
+	if value, ok := resolvePath(input, steps); ok {
+		return value, nil
+	}
+	if len(fallback) == 1 {
+		return fallback[0], nil
+	}
+	if strict {
+		return nil, errors.New(fmt.Sprintf("map has no entry for key path \"%s\"", path))
+	}
+	return nil, nil
+}
+
+// lookupFunc returns the lookup function, which finds a path in dictionary: {{ lookup "clusters.dev.endpoints[0]" }}
+func lookupFunc(dictionary map[string]interface{}, strict bool) func(string, ...interface{}) (interface{}, error) {
+	return func(path string, fallback ...interface{}) (interface{}, error) {
+		return getPath(dictionary, path, strict, fallback)
+	}
+}
+
+// getFunc returns the get function, which finds a path in any map or list: {{ get .clusters "dev.endpoint" "localhost" }}
+func getFunc(strict bool) func(interface{}, string, ...interface{}) (interface{}, error) {
+	return func(input interface{}, path string, fallback ...interface{}) (interface{}, error) {
+		return getPath(input, path, strict, fallback)
+	}
+}
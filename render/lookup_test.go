@@ -0,0 +1,96 @@
+package render
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var lookupDictionary = map[string]interface{}{
+	"environment": "dev",
+	"clusters": map[string]interface{}{
+		"dev": map[interface{}]interface{}{
+			"eu": map[string]string{"endpoint": "dev.eu.example.com"},
+		},
+		"prod": map[string]interface{}{
+			"endpoints": []interface{}{"a.example.com", map[string]interface{}{"host": "b.example.com"}},
+		},
+	},
+	"ports":      []int64{80, 443},
+	"dotted.key": "value",
+	"empty":      nil,
+}
+
+func TestParseLookupPath(t *testing.T) {
+	steps, err := parseLookupPath(`a.b[2][0].c\.d`)
+	assert.Nil(t, err, "unexpected error")
+	assert.Equal(t, []pathStep{{key: "a"}, {key: "b"}, {index: 2, isIndex: true}, {index: 0, isIndex: true}, {key: "c.d"}}, steps, "unexpected result")
+	steps, err = parseLookupPath("[1].name")
+	assert.Nil(t, err, "unexpected error")
+	assert.Equal(t, []pathStep{{index: 1, isIndex: true}, {key: "name"}}, steps, "unexpected result")
+
+	for _, path := range []string{"", "a..b", "a.[0]", "a[0", "a[x]", "a[-1]", "a[0]b"} {
+		_, err = parseLookupPath(path)
+		assert.NotNil(t, err, "expected error for "+path)
+	}
+}
+
+func TestLookup(t *testing.T) {
+	lookup := lookupFunc(lookupDictionary, false)
+	for path, expected := range map[string]interface{}{
+		"environment":                     "dev",
+		"clusters.dev.eu.endpoint":        "dev.eu.example.com",
+		"clusters.prod.endpoints[0]":      "a.example.com",
+		"clusters.prod.endpoints[1].host": "b.example.com",
+		"ports[1]":                        int64(443),
+		`dotted\.key`:                     "value",
+		"empty":                           nil,
+		"missing.key":                     nil,
+		"ports[2]":                        nil,
+		"environment.key":                 nil,
+	} {
+		value, err := lookup(path)
+		assert.Nil(t, err, "unexpected error for "+path)
+		assert.Equal(t, expected, value, "unexpected result for "+path)
+	}
+
+	value, err := lookup("clusters.test.eu.endpoint", "localhost")
+	assert.Nil(t, err, "unexpected error")
+	assert.Equal(t, "localhost", value, "the default should be used for a missing path")
+	value, err = lookup("empty", "default")
+	assert.Nil(t, err, "unexpected error")
+	assert.Nil(t, value, "the default should not be used for an existing key")
+	_, err = lookup("environment", 1, 2)
+	assert.NotNil(t, err, "only one default is allowed")
+
+	strict := lookupFunc(lookupDictionary, true)
+	_, err = strict("clusters.test")
+	assert.NotNil(t, err, "a missing path should fail in strict mode")
+	value, err = strict("clusters.test", "fallback")
+	assert.Nil(t, err, "unexpected error")
+	assert.Equal(t, "fallback", value, "the default should be used in strict mode")
+}
+
+func TestGet(t *testing.T) {
+	get := getFunc(false)
+	value, err := get(lookupDictionary["clusters"], "dev.eu.endpoint")
+	assert.Nil(t, err, "unexpected error")
+	assert.Equal(t, "dev.eu.example.com", value, "unexpected result")
+	value, err = get([]interface{}{map[string]string{"name": "web"}}, "[0].name")
+	assert.Nil(t, err, "unexpected error")
+	assert.Equal(t, "web", value, "unexpected result")
+	value, err = get(nil, "a", "b")
+	assert.Nil(t, err, "unexpected error")
+	assert.Equal(t, "b", value, "unexpected result")
+}
+
+func TestLookupTemplate(t *testing.T) {
+	var out bytes.Buffer
+	err := New(Options{Writer: &out, Strict: true}).RenderReader("lookup", strings.NewReader(
+		`{{ lookup (printf "clusters.%s.eu.endpoint" .environment) }} {{ get .clusters "prod.endpoints[1].host" }} {{ lookup "clusters.test.eu.endpoint" "localhost" }}`),
+		lookupDictionary)
+	assert.Nil(t, err, "unexpected error")
+	assert.Equal(t, "dev.eu.example.com b.example.com localhost", out.String(), "unexpected result")
+}
@@ -0,0 +1,263 @@
+package render
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// toNumber converts input to int64 or float64. Integers stay integers, everything else is a float.
+func toNumber(input interface{}) (interface{}, error) {
+	switch value := input.(type) {
+	case int:
+		return int64(value), nil
+	case int8:
+		return int64(value), nil
+	case int16:
+		return int64(value), nil
+	case int32:
+		return int64(value), nil
+	case int64:
+		return value, nil
+	case uint:
+		return uintToNumber(uint64(value))
+	case uint8:
+		return int64(value), nil
+	case uint16:
+		return int64(value), nil
+	case uint32:
+		return int64(value), nil
+	case uint64:
+		return uintToNumber(value)
+	case float32:
+		return finite(float64(value))
+	case float64:
+		return finite(value)
+	case string:
+		// Some users might quote their numbers
+		if i, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return i, nil
+		}
+		// ParseFloat also accepts "inf" and "nan", which finite rejects
+		if f, err := strconv.ParseFloat(value, 64); err == nil {
+			return finite(f)
+		}
+	}
+	return nil, errors.New(fmt.Sprintf("cannot convert input to number: %v", input))
+}
+
+// finite returns value, or an error if it is infinite or not a number.
+func finite(value float64) (interface{}, error) {
+	if math.IsInf(value, 0) || math.IsNaN(value) {
+		return nil, errors.New(fmt.Sprintf("not a finite number: %v", value))
+	}
+	return value, nil
+}
+
+// floatResult returns the result of a float operation, or an error if it overflowed.
+func floatResult(result float64, a float64, operator string, b float64) (interface{}, error) {
+	if math.IsInf(result, 0) || math.IsNaN(result) {
+		return nil, errors.New(fmt.Sprintf("float overflow: %v %s %v", a, operator, b))
+	}
+	return result, nil
+}
+
+func uintToNumber(value uint64) (interface{}, error) {
+	if value > math.MaxInt64 {
+		return nil, errors.New(fmt.Sprintf("integer overflow: %d", value))
+	}
+	return int64(value), nil
+}
+
+// toNumbers converts a and b to numbers. If both are integers, ints is true, otherwise fa and fb hold the floats.
+func toNumbers(a interface{}, b interface{}) (ia, ib int64, fa, fb float64, ints bool, err error) {
+	var na, nb interface{}
+	if na, err = toNumber(a); err != nil {
+		return
+	}
+	if nb, err = toNumber(b); err != nil {
+		return
+	}
+	ia, aIsInt := na.(int64)
+	ib, bIsInt := nb.(int64)
+	if aIsInt && bIsInt {
+		return ia, ib, 0, 0, true, nil
+	}
+	return 0, 0, toFloat(na), toFloat(nb), false, nil
+}
+
+func toFloat(number interface{}) float64 {
+	if i, ok := number.(int64); ok {
+		return float64(i)
+	}
+	return number.(float64)
+}
+
+func add(a interface{}, b interface{}) (interface{}, error) {
+	ia, ib, fa, fb, ints, err := toNumbers(a, b)
+	if err != nil {
+		return nil, err
+	}
+	if !ints {
+		return floatResult(fa+fb, fa, "+", fb)
+	}
+	if (ib > 0 && ia > math.MaxInt64-ib) || (ib < 0 && ia < math.MinInt64-ib) {
+		return nil, errors.New(fmt.Sprintf("integer overflow: %d + %d", ia, ib))
+	}
+	return ia + ib, nil
+}
+
+func sub(a interface{}, b interface{}) (interface{}, error) {
+	ia, ib, fa, fb, ints, err := toNumbers(a, b)
+	if err != nil {
+		return nil, err
+	}
+	if !ints {
+		return floatResult(fa-fb, fa, "-", fb)
+	}
+	if (ib < 0 && ia > math.MaxInt64+ib) || (ib > 0 && ia < math.MinInt64+ib) {
+		return nil, errors.New(fmt.Sprintf("integer overflow: %d - %d", ia, ib))
+	}
+	return ia - ib, nil
+}
+
+func mul(a interface{}, b interface{}) (interface{}, error) {
+	ia, ib, fa, fb, ints, err := toNumbers(a, b)
+	if err != nil {
+		return nil, err
+	}
+	if !ints {
+		return floatResult(fa*fb, fa, "*", fb)
+	}
+	result := ia * ib
+	if ia != 0 && (result/ia != ib || (ia == -1 && ib == math.MinInt64)) {
+		return nil, errors.New(fmt.Sprintf("integer overflow: %d * %d", ia, ib))
+	}
+	return result, nil
+}
+
+// div divides a by b. The division of two integers is an integer, truncated toward zero.
+func div(a interface{}, b interface{}) (interface{}, error) {
+	ia, ib, fa, fb, ints, err := toNumbers(a, b)
+	if err != nil {
+		return nil, err
+	}
+	if (ints && ib == 0) || (!ints && fb == 0) {
+		return nil, errors.New("division by zero")
+	}
+	if !ints {
+		return floatResult(fa/fb, fa, "/", fb)
+	}
+	if ia == math.MinInt64 && ib == -1 {
+		return nil, errors.New(fmt.Sprintf("integer overflow: %d / %d", ia, ib))
+	}
+	return ia / ib, nil
+}
+
+// mod returns the remainder of a divided by b. It has the sign of a.
+func mod(a interface{}, b interface{}) (interface{}, error) {
+	ia, ib, fa, fb, ints, err := toNumbers(a, b)
+	if err != nil {
+		return nil, err
+	}
+	if (ints && ib == 0) || (!ints && fb == 0) {
+		return nil, errors.New("division by zero")
+	}
+	if !ints {
+		return math.Mod(fa, fb), nil
+	}
+	if ib == -1 {
+		return int64(0), nil
+	}
+	return ia % ib, nil
+}
+
+func min(first interface{}, others ...interface{}) (interface{}, error) {
+	return extreme(append([]interface{}{first}, others...), true)
+}
+
+func max(first interface{}, others ...interface{}) (interface{}, error) {
+	return extreme(append([]interface{}{first}, others...), false)
+}
+
+// extreme returns the smallest or the largest number. The result is an integer if all numbers are integers.
+func extreme(inputs []interface{}, smallest bool) (interface{}, error) {
+	numbers := make([]interface{}, 0, len(inputs))
+	allInts := true
+	for _, input := range inputs {
+		number, err := toNumber(input)
+		if err != nil {
+			return nil, err
+		}
+		_, isInt := number.(int64)
+		allInts = allInts && isInt
+		numbers = append(numbers, number)
+	}
+	if allInts {
+		result := numbers[0].(int64)
+		for _, number := range numbers[1:] {
+			if i := number.(int64); (i < result) == smallest && i != result {
+				result = i
+			}
+		}
+		return result, nil
+	}
+	result := toFloat(numbers[0])
+	for _, number := range numbers[1:] {
+		if f := toFloat(number); (f < result) == smallest && f != result {
+			result = f
+		}
+	}
+	return result, nil
+}
+
+func floor(input interface{}) (interface{}, error) {
+	return roundWith(input, math.Floor)
+}
+
+func ceil(input interface{}) (interface{}, error) {
+	return roundWith(input, math.Ceil)
+}
+
+// round rounds half away from zero. The optional precision sets the number of decimal places to keep.
+func round(input interface{}, precision ...interface{}) (interface{}, error) {
+	if len(precision) == 0 {
+		return roundWith(input, math.Round)
+	}
+	if len(precision) > 1 {
+		return nil, errors.New("round takes at most one precision")
+	}
+	number, err := toNumber(input)
+	if err != nil {
+		return nil, err
+	}
+	places, err := toNumber(precision[0])
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := places.(int64); !ok {
+		return nil, errors.New(fmt.Sprintf("precision is not an integer: %v", precision[0]))
+	}
+	if i, ok := number.(int64); ok {
+		return i, nil
+	}
+	scale := math.Pow(10, float64(places.(int64)))
+	result := math.Round(number.(float64)*scale) / scale
+	if math.IsInf(result, 0) || math.IsNaN(result) {
+		return nil, errors.New(fmt.Sprintf("precision out of range: %v", precision[0]))
+	}
+	return result, nil
+}
+
+// roundWith applies rounding to floats. Integers are returned unchanged.
+func roundWith(input interface{}, rounding func(float64) float64) (interface{}, error) {
+	number, err := toNumber(input)
+	if err != nil {
+		return nil, err
+	}
+	if f, ok := number.(float64); ok {
+		return rounding(f), nil
+	}
+	return number, nil
+}
@@ -0,0 +1,131 @@
+package render
+
+import (
+	"io/ioutil"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestArithmetic(t *testing.T) {
+	for _, test := range []struct {
+		function func(interface{}, interface{}) (interface{}, error)
+		a, b     interface{}
+		expected interface{}
+	}{
+		{add, 3, 5, int64(8)},
+		{add, float64(3), float64(5), float64(8)},
+		{add, 1.5, 2, 3.5},
+		{add, "-2", int64(1), int64(-1)},
+		{sub, 2, 5, int64(-3)},
+		{sub, 8, uint64(2), int64(6)},
+		{sub, 2.5, 0.5, 2.0},
+		{mul, -3, 4, int64(-12)},
+		{mul, 1.5, 4, 6.0},
+		{div, 7, 2, int64(3)},
+		{div, -7, 2, int64(-3)},
+		{div, 7.0, 2, 3.5},
+		{mod, 7, 3, int64(1)},
+		{mod, -7, 3, int64(-1)},
+		{mod, 7.5, 2, 1.5},
+		{mod, int64(math.MinInt64), -1, int64(0)},
+	} {
+		result, err := test.function(test.a, test.b)
+		assert.Nil(t, err, "unexpected error")
+		assert.Equal(t, test.expected, result, "unexpected result")
+	}
+}
+
+func TestArithmeticErrors(t *testing.T) {
+	for _, test := range []struct {
+		function func(interface{}, interface{}) (interface{}, error)
+		a, b     interface{}
+	}{
+		{add, int64(math.MaxInt64), 1},
+		{add, int64(math.MinInt64), -1},
+		{sub, int64(math.MinInt64), 1},
+		{sub, int64(math.MaxInt64), -1},
+		{mul, int64(math.MaxInt64), 2},
+		{mul, -1, int64(math.MinInt64)},
+		{div, int64(math.MinInt64), -1},
+		{div, 1, 0},
+		{div, 1.0, 0.0},
+		{mod, 1, 0},
+		{add, uint64(math.MaxUint64), 0},
+		{add, "one", 1},
+		{add, []int{1}, 1},
+		{mul, 1e308, 10},
+		{add, 1.7e308, 1.7e308},
+		{sub, -1.7e308, 1.7e308},
+		{div, 1e308, 1e-308},
+		{add, "nan", 1},
+		{add, 1, "inf"},
+		{sub, "-Infinity", 1},
+		{mul, math.Inf(1), 1},
+		{mod, math.NaN(), 1},
+		{add, float32(math.Inf(-1)), 1},
+	} {
+		_, err := test.function(test.a, test.b)
+		assert.NotNil(t, err, "expected error")
+	}
+
+	for _, text := range []string{`{{ mul 1e308 10 }}`, `{{ add "nan" 1 }}`} {
+		err := New(Options{Writer: ioutil.Discard}).RenderReader("math", strings.NewReader(text), nil)
+		assert.NotNil(t, err, "expected error for "+text)
+	}
+}
+
+func TestMinMax(t *testing.T) {
+	result, err := min(3, -1, int64(2))
+	assert.Nil(t, err, "unexpected error")
+	assert.Equal(t, int64(-1), result, "unexpected result")
+
+	result, err = max(3, 4.5, 1)
+	assert.Nil(t, err, "unexpected error")
+	assert.Equal(t, 4.5, result, "unexpected result")
+
+	result, err = max(int64(math.MaxInt64), int64(math.MaxInt64-1))
+	assert.Nil(t, err, "unexpected error")
+	assert.Equal(t, int64(math.MaxInt64), result, "integers should not lose precision")
+
+	_, err = min(1, "x")
+	assert.NotNil(t, err, "expected error")
+}
+
+func TestRounding(t *testing.T) {
+	for _, test := range []struct {
+		function func(interface{}) (interface{}, error)
+		input    interface{}
+		expected interface{}
+	}{
+		{floor, 2.7, 2.0},
+		{floor, -2.2, -3.0},
+		{floor, 5, int64(5)},
+		{ceil, 2.2, 3.0},
+		{ceil, "-2.7", -2.0},
+	} {
+		result, err := test.function(test.input)
+		assert.Nil(t, err, "unexpected error")
+		assert.Equal(t, test.expected, result, "unexpected result")
+	}
+
+	result, err := round(2.5)
+	assert.Nil(t, err, "unexpected error")
+	assert.Equal(t, 3.0, result, "unexpected result")
+	result, err = round(-2.5)
+	assert.Nil(t, err, "unexpected error")
+	assert.Equal(t, -3.0, result, "unexpected result")
+	result, err = round(3.14159, 2)
+	assert.Nil(t, err, "unexpected error")
+	assert.Equal(t, 3.14, result, "unexpected result")
+	_, err = round(3.14159, 1.5)
+	assert.NotNil(t, err, "precision should be an integer")
+	_, err = round(3.14159, 1, 2)
+	assert.NotNil(t, err, "too many arguments")
+	_, err = round(1e308, 10)
+	assert.NotNil(t, err, "rounding should not overflow")
+	_, err = round(3.14159, -400)
+	assert.NotNil(t, err, "rounding should not return NaN")
+}
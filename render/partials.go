@@ -0,0 +1,59 @@
+package render
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+// maxIncludeDepth stops templates that include themselves.
+const maxIncludeDepth = 100
+
+// parsePartials parses the template files in the Partials directories into base.
+// Each file is named after its path relative to its partials directory.
+func (r *Renderer) parsePartials(base *template.Template) error {
+	for _, dir := range r.options.Partials {
+		err := filepath.Walk(dir, func(currentPath string, pathInfo os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if pathInfo.IsDir() || (!r.options.All && filepath.Ext(currentPath) != r.options.Extension) {
+				return nil
+			}
+			content, err := ioutil.ReadFile(currentPath)
+			if err != nil {
+				return err
+			}
+			name, err := filepath.Rel(dir, currentPath)
+			if err != nil {
+				return err
+			}
+			_, err = r.newSource(filepath.ToSlash(name), content).parse(base)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// includeFunc returns the include function of set. Include executes a template of the set and returns the result
+// as a string, so it can be used in a pipeline: {{ include "labels" . | indent 4 }}
+func includeFunc(set *template.Template) func(string, interface{}) (string, error) {
+	depth := 0
+	return func(name string, data interface{}) (string, error) {
+		if depth >= maxIncludeDepth {
+			return "", errors.New(fmt.Sprintf("include %q: too many nested includes", name))
+		}
+		depth++
+		defer func() { depth-- }()
+		var result strings.Builder
+		err := set.ExecuteTemplate(&result, name, data)
+		return result.String(), err
+	}
+}
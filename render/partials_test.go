@@ -0,0 +1,41 @@
+package render
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPartials(t *testing.T) {
+	dictionary := map[string]interface{}{"user": "guest", "filename": "test"}
+
+	var result bytes.Buffer
+	renderer := New(Options{Writer: &result, Partials: []string{filepath.Join(rootDir, "test_partials")}})
+	err := renderer.Render(filepath.Join(rootDir, "test_templates2", "partials.template"), dictionary)
+	assert.Nil(t, err, "unexpected error")
+	assert.Equal(t, "Hi guest!\nlabels:\n  user: guest\n  filename: test\n", result.String(), "unexpected result")
+
+	// Defines of one template do not leak into the next one
+	result.Reset()
+	err = renderer.RenderReader("first", strings.NewReader(`{{ define "local" }}local{{ end }}{{ template "local" }}`), dictionary)
+	assert.Nil(t, err, "unexpected error")
+	err = renderer.RenderReader("second", strings.NewReader(`{{ template "local" }}`), dictionary)
+	assert.NotNil(t, err, "define should not leak between templates")
+
+	// Files without the template extension are not partials
+	err = renderer.RenderReader("readme", strings.NewReader(`{{ template "README.md" }}`), dictionary)
+	assert.NotNil(t, err, "README.md is not a partial")
+
+	err = New(Options{Partials: []string{"nonexistent"}}).RenderReader("missing", strings.NewReader(""), dictionary)
+	assert.NotNil(t, err, "missing partials directory should fail")
+}
+
+func TestIncludeRecursion(t *testing.T) {
+	var result bytes.Buffer
+	err := New(Options{Writer: &result}).RenderReader("loop", strings.NewReader(`{{ define "self" }}{{ include "self" . }}{{ end }}{{ include "self" . }}`), nil)
+	assert.NotNil(t, err, "recursion should fail")
+	assert.Contains(t, err.Error(), "too many nested includes", "unexpected error")
+}
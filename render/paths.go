@@ -0,0 +1,47 @@
+package render
+
+import (
+	"path/filepath"
+)
+
+// Context describes the template being executed. Templates get it from the stemplate function:
+// {{ stemplate.Template.Path }}
+type Context struct {
+	Template TemplateContext
+	Output   OutputContext
+}
+
+// TemplateContext describes the template file being executed.
+type TemplateContext struct {
+	// Path is the path of the template file, as it was found in the input.
+	Path string
+	// Name is the file name of the template.
+	Name string
+}
+
+// OutputContext describes where the results of the template are written.
+type OutputContext struct {
+	// Path is the path of the result file. It is empty if the results are written to Writer.
+	Path string
+}
+
+// contextFunc returns the stemplate function for the template at path that writes to output.
+func contextFunc(path string, output string) func() Context {
+	context := Context{Output: OutputContext{Path: output}}
+	if path != "" {
+		context.Template = TemplateContext{Path: path, Name: filepath.Base(path)}
+	}
+	return func() Context {
+		return context
+	}
+}
+
+// joinPath joins path elements with the separator of the operating system.
+func joinPath(elements ...string) string {
+	return filepath.Join(elements...)
+}
+
+// relPath returns target relative to basepath. Use it in a pipeline: {{ .certFile | relPath "/etc" }}
+func relPath(basepath string, target string) (string, error) {
+	return filepath.Rel(basepath, target)
+}
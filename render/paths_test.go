@@ -0,0 +1,50 @@
+package render
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPathFunctions(t *testing.T) {
+	assert.Equal(t, filepath.Join("etc", "nginx", "nginx.conf"), joinPath("etc", "nginx", "nginx.conf"), "unexpected result")
+	result, err := relPath("/etc", "/etc/nginx/nginx.conf")
+	assert.Nil(t, err, "unexpected error")
+	assert.Equal(t, filepath.Join("nginx", "nginx.conf"), result, "unexpected result")
+	_, err = relPath("/etc", "nginx.conf")
+	assert.NotNil(t, err, "cannot make a relative path absolute")
+
+	var out bytes.Buffer
+	err = New(Options{Writer: &out}).RenderReader("paths", strings.NewReader(`{{ base .path }} {{ dir .path }} {{ ext .path }} {{ clean "a/../b/" }}`),
+		map[string]interface{}{"path": "/etc/nginx/nginx.conf"})
+	assert.Nil(t, err, "unexpected error")
+	assert.Equal(t, "nginx.conf /etc/nginx .conf b", out.String(), "unexpected result")
+}
+
+func TestContext(t *testing.T) {
+	templateFile := filepath.Join(rootDir, "test_templates2", "context.template")
+
+	var out bytes.Buffer
+	err := New(Options{Writer: &out}).Render(templateFile, nil)
+	assert.Nil(t, err, "unexpected error")
+	assert.Equal(t, "template context.template in test_templates2 writes .\n", out.String(), "the output path should be empty")
+
+	outputDir, err := ioutil.TempDir("", "stemplate")
+	assert.Nil(t, err, "unexpected error")
+	defer os.RemoveAll(outputDir)
+	err = New(Options{Output: outputDir}).Render(templateFile, nil)
+	assert.Nil(t, err, "unexpected error")
+	result, err := ioutil.ReadFile(filepath.Join(outputDir, "context"))
+	assert.Nil(t, err, "unexpected error")
+	assert.Equal(t, "template context.template in test_templates2 writes context\n", string(result), "unexpected result")
+
+	out.Reset()
+	err = New(Options{Writer: &out}).RenderReader("stdin", strings.NewReader(`{{ define "name" }}{{ stemplate.Template.Name }}{{ end }}{{ template "name" }}`), nil)
+	assert.Nil(t, err, "unexpected error")
+	assert.Equal(t, "stdin", out.String(), "the context should be available in defined templates")
+}
@@ -0,0 +1,65 @@
+package render
+
+import (
+	"regexp"
+)
+
+func regexMatch(regex string, s string) (bool, error) {
+	return regexp.MatchString(regex, s)
+}
+
+// regexFind returns the first match of regex in s, or an empty string if there is none.
+func regexFind(regex string, s string) (string, error) {
+	re, err := regexp.Compile(regex)
+	if err != nil {
+		return "", err
+	}
+	return re.FindString(s), nil
+}
+
+// regexFindAll returns all the matches of regex in s.
+func regexFindAll(regex string, s string) ([]string, error) {
+	re, err := regexp.Compile(regex)
+	if err != nil {
+		return nil, err
+	}
+	return re.FindAllString(s, -1), nil
+}
+
+// regexReplaceAll replaces the matches of regex in s. The replacement can refer to groups as $1 or ${name}.
+func regexReplaceAll(regex string, replacement string, s string) (string, error) {
+	re, err := regexp.Compile(regex)
+	if err != nil {
+		return "", err
+	}
+	return re.ReplaceAllString(s, replacement), nil
+}
+
+// regexSplit splits s around the matches of regex.
+func regexSplit(regex string, s string) ([]string, error) {
+	re, err := regexp.Compile(regex)
+	if err != nil {
+		return nil, err
+	}
+	return re.Split(s, -1), nil
+}
+
+// regexCapture returns the named groups of the first match of regex in s.
+// The map is empty if there is no match. Groups that did not take part in the match are empty strings.
+func regexCapture(regex string, s string) (map[string]string, error) {
+	re, err := regexp.Compile(regex)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string]string)
+	match := re.FindStringSubmatch(s)
+	if match == nil {
+		return result, nil
+	}
+	for i, name := range re.SubexpNames() {
+		if name != "" {
+			result[name] = match[i]
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,57 @@
+package render
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegex(t *testing.T) {
+	matched, err := regexMatch(`^[a-z]+:\d+$`, "nginx:1")
+	assert.Nil(t, err, "unexpected error")
+	assert.True(t, matched, "unexpected result")
+
+	found, err := regexFind(`\d+\.\d+`, "nginx:1.19-alpine")
+	assert.Nil(t, err, "unexpected error")
+	assert.Equal(t, "1.19", found, "unexpected result")
+
+	all, err := regexFindAll(`\d+`, "10.0.0.1")
+	assert.Nil(t, err, "unexpected error")
+	assert.Equal(t, []string{"10", "0", "0", "1"}, all, "unexpected result")
+
+	replaced, err := regexReplaceAll(`^(?P<host>[^.]+)\.(.*)$`, "${host}-internal.$2", "api.example.com")
+	assert.Nil(t, err, "unexpected error")
+	assert.Equal(t, "api-internal.example.com", replaced, "unexpected result")
+
+	parts, err := regexSplit(`[,;]\s*`, "a, b;c")
+	assert.Nil(t, err, "unexpected error")
+	assert.Equal(t, []string{"a", "b", "c"}, parts, "unexpected result")
+
+	captured, err := regexCapture(`^(?P<image>[^:]+):(?P<tag>.+)$`, "registry/nginx:1.19")
+	assert.Nil(t, err, "unexpected error")
+	assert.Equal(t, map[string]string{"image": "registry/nginx", "tag": "1.19"}, captured, "unexpected result")
+	captured, err = regexCapture(`^(?P<image>[^:]+):(?P<tag>.+)$`, "nginx")
+	assert.Nil(t, err, "unexpected error")
+	assert.Empty(t, captured, "unexpected result")
+
+	for _, f := range []func() error{
+		func() error { _, err := regexMatch("(", "a"); return err },
+		func() error { _, err := regexFind("(", "a"); return err },
+		func() error { _, err := regexFindAll("(", "a"); return err },
+		func() error { _, err := regexReplaceAll("(", "", "a"); return err },
+		func() error { _, err := regexSplit("(", "a"); return err },
+		func() error { _, err := regexCapture("(", "a"); return err },
+	} {
+		assert.NotNil(t, f(), "expected error")
+	}
+}
+
+func TestRegexTemplate(t *testing.T) {
+	var out bytes.Buffer
+	r := New(Options{Writer: &out})
+	err := r.RenderReader("regex", bytes.NewBufferString(`{{ $image := regexCapture "^(?P<repo>[^:]+):(?P<tag>.+)$" .image }}{{ $image.repo }} {{ $image.tag }} {{ .host | regexReplaceAll "\\..*$" "" }}`),
+		map[string]interface{}{"image": "nginx:1.19", "host": "api.example.com"})
+	assert.Nil(t, err, "unexpected error")
+	assert.Equal(t, "nginx 1.19 api", out.String(), "unexpected result")
+}
@@ -0,0 +1,253 @@
+// Package render fills in text/template files and directories with values from a dictionary.
+// It keeps no global state, so a Renderer can be shared between goroutines.
+package render
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// DefaultExtension marks template files when the template input or output is a directory.
+const DefaultExtension = ".template"
+
+// Options configure a Renderer.
+type Options struct {
+	// Extension marks template files when the template input or output is a directory. Default: DefaultExtension
+	Extension string
+	// All considers all files in a directory templates, regardless of extension.
+	All bool
+	// Output is the file or directory where results are written. Results go to Writer if empty.
+	Output string
+	// Writer receives the results when Output is empty. Default: os.Stdout
+	Writer io.Writer
+	// Funcs are added to the template functions. They override the built-in functions with the same name.
+	Funcs template.FuncMap
+	// Strict fails the rendering when a template refers to a key that is missing from the dictionary.
+	Strict bool
+	// ClampStrings limits the indices of left, right, mid and substr to the string instead of failing.
+	ClampStrings bool
+	// LeftDelim and RightDelim are the template delimiters. Default: DefaultLeftDelim and DefaultRightDelim
+	// A template can change them with a header comment: {{/* delims "[[" "]]" */}}
+	LeftDelim  string
+	RightDelim string
+	// Now is the time returned by the now function. The current time is used if it is zero.
+	Now time.Time
+	// Partials are directories of templates that are parsed into every template, so their {{ define }}
+	// blocks can be used by {{ template }} and include. Files are selected by Extension and All.
+	Partials []string
+}
+
+// Renderer parses templates and executes them with a dictionary.
+type Renderer struct {
+	options Options
+}
+
+// New returns a Renderer that uses options.
+func New(options Options) *Renderer {
+	if options.Extension == "" {
+		options.Extension = DefaultExtension
+	}
+	if options.Writer == nil {
+		options.Writer = os.Stdout
+	}
+	if options.LeftDelim == "" {
+		options.LeftDelim = DefaultLeftDelim
+	}
+	if options.RightDelim == "" {
+		options.RightDelim = DefaultRightDelim
+	}
+	return &Renderer{options: options}
+}
+
+// Render executes the templates in input with dictionary.
+// Input is a file, a directory or a comma-separated list of files and directories.
+func (r *Renderer) Render(input string, dictionary map[string]interface{}) (err error) {
+	base, err := r.base(dictionary)
+	if err != nil {
+		return
+	}
+
+	templateIsComplex := true // Assuming we have a list of files and directories
+	templateIsDir := false
+	if templateInfo, checkErr := os.Stat(input); checkErr == nil {
+		templateIsDir = templateInfo.IsDir()
+		templateIsComplex = false
+	}
+
+	// Output path
+	outputIsDir := false
+	if r.options.Output != "" {
+		outputInfo, checkErr := os.Stat(r.options.Output)
+		outputExist := checkErr == nil
+		if outputExist {
+			outputIsDir = outputInfo.IsDir()
+		}
+
+		if (templateIsComplex || templateIsDir) && !outputExist {
+			err = os.MkdirAll(r.options.Output, os.ModePerm)
+			if err != nil {
+				return
+			}
+		}
+		if (templateIsComplex || templateIsDir) && outputExist && !outputIsDir {
+			return errors.New("cannot copy template folder into file")
+		}
+	}
+
+	for _, templateFileOrDir := range strings.Split(input, ",") {
+		err = filepath.Walk(templateFileOrDir, func(currentPath string, pathInfo os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+
+			if r.options.Output == "" { // Print to screen instead of file
+				// If the current path is a directory, move on
+				if pathInfo.IsDir() {
+					return nil
+				}
+				// If extension does not match and we do not process all files in the template directory, then print the file and move on
+				if (templateIsComplex || templateIsDir) && !r.options.All && filepath.Ext(currentPath) != r.options.Extension {
+					regularFileContent, openError := ioutil.ReadFile(currentPath)
+					if openError != nil {
+						return openError
+					}
+					_, err = r.options.Writer.Write(regularFileContent)
+					return err
+				}
+				return r.executeFile(r.options.Writer, base, currentPath, "", dictionary)
+			}
+
+			var destination string
+			// (file-to-file) source is a simple file, destination is a folder or a file
+			if !templateIsComplex && !templateIsDir {
+				if pathInfo.IsDir() { // source is under multiple folders
+					return nil
+				}
+				if outputIsDir {
+					destination = filepath.Join(r.options.Output, filepath.Base(currentPath))
+				} else {
+					destination = r.options.Output
+				}
+			}
+			// (dir-to-dir) source is one directory, use the contents only
+			if !templateIsComplex && templateIsDir {
+				relativeRoot := filepath.Clean(templateFileOrDir)
+				cleanCurrentPath := filepath.Clean(currentPath)
+				if currentPath == templateFileOrDir || relativeRoot == cleanCurrentPath { // do not copy the source's root folder
+					return nil
+				}
+				relativePath := filepath.Clean(strings.Replace(cleanCurrentPath, relativeRoot, "", 1))
+				destination = filepath.Join(r.options.Output, relativePath)
+			}
+			// (multi-to-dir) source is a list of files and directories, copy source folders too
+			if templateIsComplex {
+				destination = filepath.Join(r.options.Output, currentPath)
+			}
+			// if the current path is a directory, create it at output (should only run when multi|dir-to-dir)
+			if pathInfo.IsDir() {
+				return os.MkdirAll(destination, pathInfo.Mode())
+			}
+			// If extension does not match and we do not process all files in the template directory, then copy file and move on
+			if (templateIsComplex || templateIsDir) && !r.options.All && filepath.Ext(destination) != r.options.Extension {
+				return os.Link(currentPath, destination)
+			}
+			// Cut off .template extension
+			extension := filepath.Ext(destination)
+			if extension == r.options.Extension {
+				destination = destination[0 : len(destination)-len(extension)]
+			}
+			// Create and open file
+			out, err := os.Create(destination)
+			if err != nil {
+				return err
+			}
+			defer out.Close()
+			return r.executeFile(out, base, currentPath, destination, dictionary)
+		})
+		if err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+// base returns the template set that every template is parsed into.
+// It holds the template functions, the options and the partials.
+func (r *Renderer) base(dictionary map[string]interface{}) (*template.Template, error) {
+	base := template.New("")
+	base.Funcs(funcMap(dictionary, r.options)).Funcs(fileFuncs(base, "", "")).Funcs(r.options.Funcs)
+	if r.options.Strict {
+		base = base.Option("missingkey=error")
+	}
+	return base, r.parsePartials(base)
+}
+
+// RenderReader executes a single template read from in with dictionary. Name is used in error messages.
+// The results are written to the Output file, or to Writer if Output is empty.
+func (r *Renderer) RenderReader(name string, in io.Reader, dictionary map[string]interface{}) error {
+	content, err := ioutil.ReadAll(in)
+	if err != nil {
+		return err
+	}
+	base, err := r.base(dictionary)
+	if err != nil {
+		return err
+	}
+
+	if r.options.Output == "" {
+		return r.execute(r.options.Writer, base, name, "", content, dictionary)
+	}
+	if outputInfo, checkErr := os.Stat(r.options.Output); checkErr == nil && outputInfo.IsDir() {
+		return errors.New("cannot write template without a filename into folder")
+	}
+	out, err := os.Create(r.options.Output)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+	return r.execute(out, base, name, r.options.Output, content, dictionary)
+}
+
+// executeFile parses the template file at path and writes the results to out.
+// Output is the path of the result file, or empty if out is Writer.
+func (r *Renderer) executeFile(out io.Writer, base *template.Template, path string, output string, dictionary map[string]interface{}) error {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return r.execute(out, base, path, output, content, dictionary)
+}
+
+// execute parses the template content into a copy of base and writes the results to out.
+// The template is named after its path, so errors point to the file, line and key that failed.
+// If the template extends a base template, the base is executed with the blocks of the template.
+func (r *Renderer) execute(out io.Writer, base *template.Template, path string, output string, content []byte, dictionary map[string]interface{}) error {
+	set, err := base.Clone()
+	if err != nil {
+		return err
+	}
+	set.Funcs(fileFuncs(set, path, output)).Funcs(r.options.Funcs)
+	chain, err := r.layouts(path, content)
+	if err != nil {
+		return err
+	}
+	// Parse the root base first, so the blocks defined by the extending templates override the blocks of their bases
+	var tmpl *template.Template
+	for i := len(chain) - 1; i >= 0; i-- {
+		parsed, err := chain[i].parse(set)
+		if err != nil {
+			return err
+		}
+		if tmpl == nil {
+			tmpl = parsed
+		}
+	}
+	return tmpl.Execute(out, dictionary)
+}
@@ -0,0 +1,119 @@
+package render
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testresult = `Hi guest!
+
+Welcome to this test template demonstration.
+
+You should see a few examples of
+* List item: first
+* Map item: testmap
+* Golang specific stuff
+`
+
+// rootDir has to be ".." for CircleCI to work correctly.
+var rootDir = ".."
+
+func TestRenderWriter(t *testing.T) {
+	dictionary := NewDictionary()
+	assert.Nil(t, dictionary.AddFile(filepath.Join(rootDir, "test_dictionaries", "test.yaml")), "unexpected error")
+
+	var result bytes.Buffer
+	renderer := New(Options{Writer: &result})
+	err := renderer.Render(filepath.Join(rootDir, "test_templates", "test.template"), dictionary.Values())
+	assert.Nil(t, err, "unexpected error")
+	assert.Equal(t, testresult, result.String(), "unexpected result")
+}
+
+func TestRenderConcurrent(t *testing.T) {
+	var wg sync.WaitGroup
+	for _, file := range []string{"test.json", "test.toml", "test.yaml"} {
+		wg.Add(1)
+		go func(file string) {
+			defer wg.Done()
+			dictionary := NewDictionary()
+			assert.Nil(t, dictionary.AddFile(filepath.Join(rootDir, "test_dictionaries", file)), "unexpected error")
+
+			var result bytes.Buffer
+			err := New(Options{Writer: &result}).Render(filepath.Join(rootDir, "test_templates", "test.template"), dictionary.Values())
+			assert.Nil(t, err, "unexpected error")
+			assert.Equal(t, testresult, result.String(), "unexpected result for "+file)
+		}(file)
+	}
+	wg.Wait()
+}
+
+func TestRenderFuncs(t *testing.T) {
+	var result bytes.Buffer
+	renderer := New(Options{
+		Writer: &result,
+		Funcs: map[string]interface{}{
+			"print": func(...interface{}) string { return "overridden" },
+		},
+	})
+	err := renderer.Render(filepath.Join(rootDir, "test_templates", "test.template"), map[string]interface{}{
+		"user":       "guest",
+		"filename":   "test",
+		"list":       []string{"first"},
+		"map":        map[string]string{"test": "testmap"},
+		"gospecific": []string{"Go", "lang"},
+	})
+	assert.Nil(t, err, "unexpected error")
+	assert.Contains(t, result.String(), "Golang specific overridden", "unexpected result")
+}
+
+func TestRenderStrict(t *testing.T) {
+	templateFile := filepath.Join(rootDir, "test_templates2", "strict.template")
+	dictionary := map[string]interface{}{
+		"user": "guest",
+		"map":  map[string]interface{}{"test": "testmap"},
+	}
+
+	var result bytes.Buffer
+	err := New(Options{Writer: &result}).Render(templateFile, dictionary)
+	assert.Nil(t, err, "unexpected error")
+	assert.Contains(t, result.String(), "Map item: <no value>", "unexpected result")
+
+	result.Reset()
+	err = New(Options{Writer: &result, Strict: true}).Render(templateFile, dictionary)
+	assert.NotNil(t, err, "missing key should fail")
+	assert.Contains(t, err.Error(), templateFile+":3:", "error should point to file and line")
+	assert.Contains(t, err.Error(), ".map.missing", "error should contain key path")
+
+	funcs := funcMap(dictionary, Options{Strict: true})
+	_, err = funcs["substitute"].(func(string) (interface{}, error))("nothere")
+	assert.NotNil(t, err, "substitute should fail on missing key")
+	value, err := funcs["substitute"].(func(string) (interface{}, error))("user")
+	assert.Nil(t, err, "unexpected error")
+	assert.Equal(t, "guest", value, "unexpected result")
+}
+
+func TestRenderReader(t *testing.T) {
+	dictionary := NewDictionary()
+	err := dictionary.AddReader("stdin", strings.NewReader(`{"user": "guest", "nested": {"Key": "value"}}`), FormatJSON)
+	assert.Nil(t, err, "unexpected error")
+
+	var result bytes.Buffer
+	err = New(Options{Writer: &result}).RenderReader("stdin", strings.NewReader("Hi {{ .user }}, {{ .nested.Key }}!\n"), dictionary.Values())
+	assert.Nil(t, err, "unexpected error")
+	assert.Equal(t, "Hi guest, value!\n", result.String(), "unexpected result")
+
+	err = New(Options{Strict: true}).RenderReader("stdin", strings.NewReader("{{ .missing }}"), dictionary.Values())
+	assert.NotNil(t, err, "missing key should fail")
+	assert.Contains(t, err.Error(), "stdin:1:", "error should point to the template name")
+
+	err = New(Options{Output: rootDir}).RenderReader("stdin", strings.NewReader("{{ .user }}"), dictionary.Values())
+	assert.NotNil(t, err, "cannot write into a folder")
+
+	err = dictionary.AddReader("stdin", strings.NewReader("user = "), FormatTOML)
+	assert.NotNil(t, err, "invalid TOML should fail")
+}
@@ -0,0 +1,103 @@
+package render
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// maxSequence limits the length of generated sequences, so a typo in a template cannot exhaust memory.
+const maxSequence = 1000000
+
+// sequence returns the integers from start towards stop in increments of step. Stop is included if inclusive is set.
+func sequence(start int64, stop int64, step int64, inclusive bool) ([]int64, error) {
+	if step == 0 {
+		return nil, errors.New("step cannot be zero")
+	}
+	result := []int64{}
+	for i := start; (step > 0 && i < stop) || (step < 0 && i > stop) || (inclusive && i == stop); i += step {
+		if len(result) == maxSequence {
+			return nil, errors.New(fmt.Sprintf("sequence longer than %d items", maxSequence))
+		}
+		result = append(result, i)
+		// Stop before the next item overflows
+		if (step > 0 && i > math.MaxInt64-step) || (step < 0 && i < math.MinInt64-step) {
+			break
+		}
+	}
+	return result, nil
+}
+
+// toInts converts inputs to 64-bit integers. Floating-point numbers are accepted if they have no fraction.
+func toInts(inputs ...interface{}) ([]int64, error) {
+	result := make([]int64, len(inputs))
+	for i, input := range inputs {
+		number, err := toNumber(input)
+		if err != nil {
+			return nil, err
+		}
+		switch value := number.(type) {
+		case int64:
+			result[i] = value
+		case float64:
+			// 2^63 is the first float outside the int64 range
+			if value != math.Trunc(value) || value < math.MinInt64 || value >= math.MaxInt64 {
+				return nil, errors.New(fmt.Sprintf("not an integer: %v", input))
+			}
+			result[i] = int64(value)
+		}
+	}
+	return result, nil
+}
+
+// seq returns the integers from start to end, including end: {{ range seq 1 3 }} is 1, 2, 3.
+// The step is 1, or -1 if end is less than start, unless it is given as a third argument.
+func seq(start interface{}, end interface{}, step ...interface{}) ([]int64, error) {
+	if len(step) > 1 {
+		return nil, errors.New("seq needs a start, an end and an optional step")
+	}
+	bounds, err := toInts(append([]interface{}{start, end}, step...)...)
+	if err != nil {
+		return nil, err
+	}
+	if len(bounds) == 2 {
+		bounds = append(bounds, 1)
+		if bounds[1] < bounds[0] {
+			bounds[2] = -1
+		}
+	}
+	return sequence(bounds[0], bounds[1], bounds[2], true)
+}
+
+// until returns the integers from 0 up to count, not including count. A negative count counts down.
+func until(count interface{}) ([]int64, error) {
+	bounds, err := toInts(count)
+	if err != nil {
+		return nil, err
+	}
+	if bounds[0] < 0 {
+		return sequence(0, bounds[0], -1, false)
+	}
+	return sequence(0, bounds[0], 1, false)
+}
+
+// counter returns the integers from 0 up to count, not including count. Unlike until, it does not count down.
+func counter(count interface{}) ([]int64, error) {
+	bounds, err := toInts(count)
+	if err != nil {
+		return nil, err
+	}
+	if bounds[0] < 0 {
+		return nil, errors.New(fmt.Sprintf("counter needs a count of at least 0: %d", bounds[0]))
+	}
+	return until(bounds[0])
+}
+
+// untilStep returns the integers from start up to stop, not including stop, in increments of step.
+func untilStep(start interface{}, stop interface{}, step interface{}) ([]int64, error) {
+	bounds, err := toInts(start, stop, step)
+	if err != nil {
+		return nil, err
+	}
+	return sequence(bounds[0], bounds[1], bounds[2], false)
+}
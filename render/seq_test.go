@@ -0,0 +1,84 @@
+package render
+
+import (
+	"bytes"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSeq(t *testing.T) {
+	result, err := seq(1, 3)
+	assert.Nil(t, err, "unexpected error")
+	assert.Equal(t, []int64{1, 2, 3}, result, "unexpected result")
+	result, err = seq(3, float64(1))
+	assert.Nil(t, err, "unexpected error")
+	assert.Equal(t, []int64{3, 2, 1}, result, "unexpected result")
+	result, err = seq(30000, 30010, 5)
+	assert.Nil(t, err, "unexpected error")
+	assert.Equal(t, []int64{30000, 30005, 30010}, result, "unexpected result")
+	result, err = seq(10, "0", -4)
+	assert.Nil(t, err, "unexpected error")
+	assert.Equal(t, []int64{10, 6, 2}, result, "unexpected result")
+	result, err = seq(1, 3, -1)
+	assert.Nil(t, err, "unexpected error")
+	assert.Equal(t, []int64{}, result, "unexpected result")
+
+	result, err = seq(3000000000, float64(3000000002))
+	assert.Nil(t, err, "unexpected error")
+	assert.Equal(t, []int64{3000000000, 3000000001, 3000000002}, result, "64-bit bounds")
+	result, err = seq(int64(math.MaxInt64-1), int64(math.MaxInt64))
+	assert.Nil(t, err, "unexpected error")
+	assert.Equal(t, []int64{math.MaxInt64 - 1, math.MaxInt64}, result, "the end should not overflow")
+	result, err = seq(int64(math.MinInt64+1), int64(math.MinInt64))
+	assert.Nil(t, err, "unexpected error")
+	assert.Equal(t, []int64{math.MinInt64 + 1, math.MinInt64}, result, "the end should not overflow")
+	result, err = untilStep(int64(math.MaxInt64-1), int64(math.MaxInt64), 5)
+	assert.Nil(t, err, "unexpected error")
+	assert.Equal(t, []int64{math.MaxInt64 - 1}, result, "the step should not overflow")
+
+	result, err = until(3)
+	assert.Nil(t, err, "unexpected error")
+	assert.Equal(t, []int64{0, 1, 2}, result, "unexpected result")
+	result, err = until(-2)
+	assert.Nil(t, err, "unexpected error")
+	assert.Equal(t, []int64{0, -1}, result, "unexpected result")
+
+	result, err = counter("3")
+	assert.Nil(t, err, "unexpected error")
+	assert.Equal(t, []int64{0, 1, 2}, result, "unexpected result")
+	result, err = counter(0)
+	assert.Nil(t, err, "unexpected error")
+	assert.Equal(t, []int64{}, result, "unexpected result")
+
+	result, err = untilStep(0, 10, 4)
+	assert.Nil(t, err, "unexpected error")
+	assert.Equal(t, []int64{0, 4, 8}, result, "unexpected result")
+	result, err = untilStep(5, 0, -2)
+	assert.Nil(t, err, "unexpected error")
+	assert.Equal(t, []int64{5, 3, 1}, result, "unexpected result")
+
+	for _, f := range []func() error{
+		func() error { _, err := seq(1, 3, 0); return err },
+		func() error { _, err := seq(1, 3, 1, 1); return err },
+		func() error { _, err := seq(1.5, 3); return err },
+		func() error { _, err := seq(0, 1e19); return err },
+		func() error { _, err := until("x"); return err },
+		func() error { _, err := counter(-1); return err },
+		func() error { _, err := counter(2.7); return err },
+		func() error { _, err := counter(maxSequence + 1); return err },
+		func() error { _, err := untilStep(0, maxSequence+1, 1); return err },
+	} {
+		assert.NotNil(t, f(), "expected error")
+	}
+}
+
+func TestSeqTemplate(t *testing.T) {
+	var out bytes.Buffer
+	r := New(Options{Writer: &out})
+	err := r.RenderReader("seq", bytes.NewBufferString(`{{ range seq 1 .replicas }}replica-{{ . }} port {{ add 30000 . }} {{ end }}`),
+		map[string]interface{}{"replicas": float64(2)})
+	assert.Nil(t, err, "unexpected error")
+	assert.Equal(t, "replica-1 port 30001 replica-2 port 30002 ", out.String(), "unexpected result")
+}
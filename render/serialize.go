@@ -0,0 +1,78 @@
+package render
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/pelletier/go-toml"
+	"gopkg.in/yaml.v2"
+)
+
+// stringKeys returns a copy of input where all maps have string keys, so it can be encoded as JSON or TOML.
+// YAML decoding produces map[interface{}]interface{} values that encoding/json cannot handle.
+func stringKeys(input interface{}) interface{} {
+	value := reflect.ValueOf(input)
+	switch value.Kind() {
+	case reflect.Map:
+		result := make(map[string]interface{}, value.Len())
+		for _, key := range value.MapKeys() {
+			result[fmt.Sprint(key.Interface())] = stringKeys(value.MapIndex(key).Interface())
+		}
+		return result
+	case reflect.Slice, reflect.Array:
+		if value.Type().Elem().Kind() == reflect.Uint8 {
+			// []byte
+			return input
+		}
+		result := make([]interface{}, value.Len())
+		for i := range result {
+			result[i] = stringKeys(value.Index(i).Interface())
+		}
+		return result
+	}
+	return input
+}
+
+func toJSON(input interface{}) (string, error) {
+	result, err := json.Marshal(stringKeys(input))
+	return string(result), err
+}
+
+func toPrettyJSON(input interface{}) (string, error) {
+	result, err := json.MarshalIndent(stringKeys(input), "", "  ")
+	return string(result), err
+}
+
+// toYAML encodes input as YAML without the trailing newline, so it can be piped into indent.
+func toYAML(input interface{}) (string, error) {
+	result, err := yaml.Marshal(input)
+	return strings.TrimSuffix(string(result), "\n"), err
+}
+
+// toTOML encodes a map as TOML.
+func toTOML(input interface{}) (string, error) {
+	values, ok := stringKeys(input).(map[string]interface{})
+	if !ok {
+		return "", errors.New(fmt.Sprintf("cannot encode %T as TOML, only maps are supported", input))
+	}
+	tree, err := toml.TreeFromMap(values)
+	if err != nil {
+		return "", err
+	}
+	return tree.ToTomlString()
+}
+
+func fromJSON(input string) (interface{}, error) {
+	return DecodeValue([]byte(input), FormatJSON)
+}
+
+func fromYAML(input string) (interface{}, error) {
+	return DecodeValue([]byte(input), FormatYAML)
+}
+
+func fromTOML(input string) (interface{}, error) {
+	return DecodeValue([]byte(input), FormatTOML)
+}
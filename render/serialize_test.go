@@ -0,0 +1,81 @@
+package render
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testSerializeInput = map[string]interface{}{
+	"name":  "app",
+	"ports": []interface{}{80, 443},
+	"labels": map[interface{}]interface{}{
+		"team": "shop",
+		1:      "one",
+	},
+}
+
+func TestToJSON(t *testing.T) {
+	result, err := toJSON(testSerializeInput)
+	assert.Nil(t, err, "unexpected error")
+	assert.Equal(t, `{"labels":{"1":"one","team":"shop"},"name":"app","ports":[80,443]}`, result, "unexpected result")
+
+	result, err = toPrettyJSON(map[string]string{"a": "b"})
+	assert.Nil(t, err, "unexpected error")
+	assert.Equal(t, "{\n  \"a\": \"b\"\n}", result, "unexpected result")
+
+	result, err = toJSON([]string{"a"})
+	assert.Nil(t, err, "unexpected error")
+	assert.Equal(t, `["a"]`, result, "unexpected result")
+
+	_, err = toJSON(map[string]interface{}{"f": func() {}})
+	assert.NotNil(t, err, "functions cannot be encoded")
+}
+
+func TestToYAML(t *testing.T) {
+	result, err := toYAML(testSerializeInput)
+	assert.Nil(t, err, "unexpected error")
+	assert.Equal(t, "labels:\n  1: one\n  team: shop\nname: app\nports:\n- 80\n- 443", result, "unexpected result")
+}
+
+func TestToTOML(t *testing.T) {
+	result, err := toTOML(map[string]interface{}{"name": "app", "server": map[string]string{"host": "localhost"}})
+	assert.Nil(t, err, "unexpected error")
+	assert.Contains(t, result, `name = "app"`, "unexpected result")
+	assert.Contains(t, result, "[server]", "unexpected result")
+	assert.Contains(t, result, `host = "localhost"`, "unexpected result")
+
+	_, err = toTOML([]string{"a"})
+	assert.NotNil(t, err, "only maps can be encoded as TOML")
+}
+
+func TestFromSerialized(t *testing.T) {
+	result, err := fromJSON(`{"a": [1, {"b": true}]}`)
+	assert.Nil(t, err, "unexpected error")
+	assert.Equal(t, map[string]interface{}{"a": []interface{}{float64(1), map[string]interface{}{"b": true}}}, result, "unexpected result")
+
+	result, err = fromYAML("a:\n  b: c\n")
+	assert.Nil(t, err, "unexpected error")
+	assert.Equal(t, map[string]interface{}{"a": map[string]interface{}{"b": "c"}}, result, "unexpected result")
+
+	result, err = fromTOML("[a]\nb = 1\n")
+	assert.Nil(t, err, "unexpected error")
+	assert.Equal(t, map[string]interface{}{"a": map[string]interface{}{"b": int64(1)}}, result, "unexpected result")
+
+	_, err = fromJSON("{")
+	assert.NotNil(t, err, "invalid JSON should fail")
+}
+
+func TestRenderSerialized(t *testing.T) {
+	dictionary := NewDictionary()
+	assert.Nil(t, dictionary.AddFile(filepath.Join(rootDir, "test_dictionaries", "test.yaml")), "unexpected error")
+
+	var result bytes.Buffer
+	template := "map:{{ toYaml .map | nindent 2 }}\nlist: {{ toJson .list }}\nfirst: {{ index (fromJson `{\"a\": [\"b\"]}`).a 0 }}\n"
+	err := New(Options{Writer: &result}).RenderReader("serialize", strings.NewReader(template), dictionary.Values())
+	assert.Nil(t, err, "unexpected error")
+	assert.Equal(t, "map:\n  nottest: not a test map\n  test: testmap\nlist: [\"first\",\"second\",\"third\"]\nfirst: b\n", result.String(), "unexpected result")
+}
@@ -0,0 +1,144 @@
+package render
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// SetPath stores value at a dot-separated path, like "a.b.c", creating the maps on the way.
+// Use "\." for a dot that is part of a key.
+func (d *Dictionary) SetPath(path string, value interface{}) error {
+	keys := splitPath(path)
+	for _, key := range keys {
+		if key == "" {
+			return errors.New(fmt.Sprintf("invalid key path: %s", path))
+		}
+	}
+	d.setKeys(keys, value)
+	return nil
+}
+
+// setKeys stores value under the nested keys, creating the maps on the way.
+func (d *Dictionary) setKeys(keys []string, value interface{}) {
+	current := d.values
+	for _, key := range keys[:len(keys)-1] {
+		var next map[string]interface{}
+		switch child := current[key].(type) {
+		case map[string]interface{}:
+			next = child
+		case map[string]string:
+			next = make(map[string]interface{}, len(child))
+			for k, v := range child {
+				next[k] = v
+			}
+		default:
+			next = make(map[string]interface{})
+		}
+		current[key] = next
+		current = next
+	}
+	current[keys[len(keys)-1]] = value
+}
+
+// AddSet parses a key.path=value expression and stores the value with its type guessed:
+// integers, floating-point numbers, true, false and null are converted, everything else is a string.
+func (d *Dictionary) AddSet(expression string) error {
+	path, value, err := splitExpression(expression)
+	if err != nil {
+		return err
+	}
+	return d.SetPath(path, guessType(value))
+}
+
+// AddSetString parses a key.path=value expression and stores the value as a string.
+func (d *Dictionary) AddSetString(expression string) error {
+	path, value, err := splitExpression(expression)
+	if err != nil {
+		return err
+	}
+	return d.SetPath(path, value)
+}
+
+// AddSetJSON parses a key.path=json expression and stores the decoded JSON value.
+func (d *Dictionary) AddSetJSON(expression string) error {
+	path, value, err := splitExpression(expression)
+	if err != nil {
+		return err
+	}
+	var decoded interface{}
+	if err = json.Unmarshal([]byte(value), &decoded); err != nil {
+		return errors.New(fmt.Sprintf("invalid JSON for %s: %s", path, err))
+	}
+	return d.SetPath(path, decoded)
+}
+
+// AddSetFile parses a key.path=filename expression and stores the content of the file as a string.
+func (d *Dictionary) AddSetFile(expression string) error {
+	path, filename, err := splitExpression(expression)
+	if err != nil {
+		return err
+	}
+	content, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+	return d.SetPath(path, string(content))
+}
+
+// splitExpression splits a key.path=value expression at the first equal sign.
+func splitExpression(expression string) (path string, value string, err error) {
+	equals := strings.Index(expression, "=")
+	if equals < 1 {
+		return "", "", errors.New(fmt.Sprintf("Missing =. %s is not a key=value pair", expression))
+	}
+	return expression[0:equals], expression[equals+1:], nil
+}
+
+// splitPath splits a dot-separated path. Escaped dots ("\.") are kept in the keys.
+func splitPath(path string) (keys []string) {
+	var key strings.Builder
+	for i := 0; i < len(path); i++ {
+		switch {
+		case path[i] == '\\' && i+1 < len(path) && path[i+1] == '.':
+			key.WriteByte('.')
+			i++
+		case path[i] == '.':
+			keys = append(keys, key.String())
+			key.Reset()
+		default:
+			key.WriteByte(path[i])
+		}
+	}
+	return append(keys, key.String())
+}
+
+// guessType converts value to int64, float64, bool or nil if it looks like one.
+// Numbers are only converted if they are written the way they would be printed, so values like 01234 (a zip code),
+// 1.10 (a version) and nan stay strings.
+func guessType(value string) interface{} {
+	switch value {
+	case "true":
+		return true
+	case "false":
+		return false
+	case "null":
+		return nil
+	}
+	if i, err := strconv.ParseInt(value, 10, 64); err == nil {
+		if strconv.FormatInt(i, 10) == value {
+			return i
+		}
+		return value
+	}
+	if f, err := strconv.ParseFloat(value, 64); err == nil && !math.IsInf(f, 0) && !math.IsNaN(f) {
+		if strconv.FormatFloat(f, 'f', -1, 64) == value {
+			return f
+		}
+	}
+	return value
+}
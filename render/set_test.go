@@ -0,0 +1,68 @@
+package render
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSet(t *testing.T) {
+	dictionary := NewDictionary()
+	dictionary.Set("map", map[string]string{"test": "testmap"})
+	dictionary.Set("user", "guest")
+
+	assert.Nil(t, dictionary.AddSet("a.b.c=42"), "unexpected error")
+	assert.Nil(t, dictionary.AddSet("a.b.pi=3.14"), "unexpected error")
+	assert.Nil(t, dictionary.AddSet("a.enabled=true"), "unexpected error")
+	assert.Nil(t, dictionary.AddSet("a.name=x=y"), "unexpected error")
+	assert.Nil(t, dictionary.AddSetString("a.version=1.10"), "unexpected error")
+	assert.Nil(t, dictionary.AddSetJSON(`a.list=[1,"two",{"three":3}]`), "unexpected error")
+	assert.Nil(t, dictionary.AddSet("map.extra=added"), "unexpected error")
+	assert.Nil(t, dictionary.AddSet("user.name=guest"), "unexpected error")
+	assert.Nil(t, dictionary.AddSet(`host\.name=example.com`), "unexpected error")
+	kept := []string{"01234", "1.10", "nan", "inf", "Infinity", "+5", "-0", "1e3"}
+	for i, value := range kept {
+		assert.Nil(t, dictionary.AddSet(fmt.Sprintf("kept.v%d=%s", i, value)), "unexpected error")
+	}
+	assert.Nil(t, dictionary.AddSet("negative=-7"), "unexpected error")
+	assert.Nil(t, dictionary.AddSet("zero=0"), "unexpected error")
+	assert.Nil(t, dictionary.AddSet("fraction=0.5"), "unexpected error")
+
+	values := dictionary.Values()
+	a := values["a"].(map[string]interface{})
+	assert.Equal(t, int64(42), a["b"].(map[string]interface{})["c"], "unexpected integer")
+	assert.Equal(t, 3.14, a["b"].(map[string]interface{})["pi"], "unexpected float")
+	assert.Equal(t, true, a["enabled"], "unexpected bool")
+	assert.Equal(t, "x=y", a["name"], "unexpected string")
+	assert.Equal(t, "1.10", a["version"], "--set-string should keep strings")
+	assert.Equal(t, []interface{}{float64(1), "two", map[string]interface{}{"three": float64(3)}}, a["list"], "unexpected JSON")
+	assert.Equal(t, map[string]interface{}{"test": "testmap", "extra": "added"}, values["map"], "map should be extended")
+	assert.Equal(t, map[string]interface{}{"name": "guest"}, values["user"], "scalar should be replaced by a map")
+	assert.Equal(t, "example.com", values["host.name"], "escaped dot should be part of the key")
+	for i, value := range kept {
+		assert.Equal(t, value, values["kept"].(map[string]interface{})[fmt.Sprintf("v%d", i)], "value should stay a string")
+	}
+	assert.Equal(t, int64(-7), values["negative"], "unexpected integer")
+	assert.Equal(t, int64(0), values["zero"], "unexpected integer")
+	assert.Equal(t, 0.5, values["fraction"], "unexpected float")
+
+	assert.NotNil(t, dictionary.AddSet("novalue"), "missing = should fail")
+	assert.NotNil(t, dictionary.AddSet("a..b=1"), "empty key should fail")
+	assert.NotNil(t, dictionary.AddSetJSON("a=[1,"), "invalid JSON should fail")
+}
+
+func TestSetFile(t *testing.T) {
+	file, err := ioutil.TempFile("", "stemplate")
+	assert.Nil(t, err, "unexpected error")
+	defer os.Remove(file.Name())
+	_, _ = file.WriteString("-----BEGIN CERTIFICATE-----\n")
+	_ = file.Close()
+
+	dictionary := NewDictionary()
+	assert.Nil(t, dictionary.AddSetFile("tls.cert="+file.Name()), "unexpected error")
+	assert.Equal(t, "-----BEGIN CERTIFICATE-----\n", dictionary.Values()["tls"].(map[string]interface{})["cert"], "unexpected file content")
+	assert.NotNil(t, dictionary.AddSetFile("tls.key=/nonexistent"), "missing file should fail")
+}
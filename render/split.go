@@ -0,0 +1,68 @@
+package render
+
+import (
+	"errors"
+	"strings"
+)
+
+// DefaultSeparator separates list items and map pairs in environment variables.
+const DefaultSeparator = ","
+
+// splitEscaped splits s at each separator that is not escaped with a backslash or inside single or double quotes.
+// A quote only opens at the start of an item, or right after an unescaped equal sign if pairs is set,
+// so apostrophes inside values are kept. The items keep their quotes and escapes, use unquote to remove them.
+// An empty string has no items.
+func splitEscaped(s string, separator string, pairs bool) (items []string, err error) {
+	if s == "" {
+		return []string{}, nil
+	}
+	var quote byte
+	start := 0
+	canOpen := true
+	for i := 0; i < len(s); i++ {
+		opening := canOpen
+		canOpen = false
+		switch {
+		case s[i] == '\\' && quote != '\'':
+			i++
+		case quote != 0:
+			if s[i] == quote {
+				quote = 0
+			}
+		case opening && (s[i] == '\'' || s[i] == '"'):
+			quote = s[i]
+		case strings.HasPrefix(s[i:], separator):
+			items = append(items, s[start:i])
+			start = i + len(separator)
+			i = start - 1
+			canOpen = true
+		case pairs && s[i] == '=':
+			canOpen = true
+		}
+	}
+	if quote != 0 {
+		return nil, errors.New("unterminated quote in " + s)
+	}
+	return append(items, s[start:]), nil
+}
+
+// unquote removes the quotes and backslash escapes from an item returned by splitEscaped.
+// Only a quote at the start of the item is removed, others are kept as they are.
+func unquote(item string) string {
+	var result strings.Builder
+	var quote byte
+	for i := 0; i < len(item); i++ {
+		switch {
+		case item[i] == '\\' && quote != '\'' && i+1 < len(item):
+			i++
+			result.WriteByte(item[i])
+		case quote != 0 && item[i] == quote:
+			quote = 0
+		case i == 0 && (item[i] == '\'' || item[i] == '"'):
+			quote = item[i]
+		default:
+			result.WriteByte(item[i])
+		}
+	}
+	return result.String()
+}
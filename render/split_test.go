@@ -0,0 +1,73 @@
+package render
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitEscaped(t *testing.T) {
+	for input, expected := range map[string][]string{
+		"":              {},
+		"a":             {"a"},
+		"a,b,c":         {"a", "b", "c"},
+		"a,,c":          {"a", "", "c"},
+		`a\,b,c`:        {"a,b", "c"},
+		`"a,b",c`:       {"a,b", "c"},
+		`'a,"b',c`:      {`a,"b`, "c"},
+		`'a\',c`:        {`a\`, "c"},
+		`"a\"b",c`:      {`a"b`, "c"},
+		`a\\,b`:         {`a\`, "b"},
+		`O'Brien,Smith`: {"O'Brien", "Smith"},
+		`it's,"a,b"`:    {"it's", "a,b"},
+		`a"b,c`:         {`a"b`, "c"},
+		`trailing\`:     {`trailing\`},
+	} {
+		items, err := splitEscaped(input, ",", false)
+		assert.Nil(t, err, "unexpected error for "+input)
+		for i, item := range items {
+			items[i] = unquote(item)
+		}
+		assert.Equal(t, expected, items, "unexpected result for "+input)
+	}
+
+	items, err := splitEscaped(`key="value, quoted",name=it's`, ",", true)
+	assert.Nil(t, err, "unexpected error")
+	assert.Equal(t, []string{`key="value, quoted"`, "name=it's"}, items, "quotes should open after an equal sign in pairs")
+
+	items, err = splitEscaped("a;;b;;c", ";;", false)
+	assert.Nil(t, err, "unexpected error")
+	assert.Equal(t, []string{"a", "b", "c"}, items, "multi-character separator")
+
+	_, err = splitEscaped(`"unterminated,a`, ",", false)
+	assert.NotNil(t, err, "unterminated quote should fail")
+}
+
+func TestDictionarySeparators(t *testing.T) {
+	_ = os.Setenv("emptytest", "")
+	_ = os.Setenv("listtest", `first;second\;half;"third;quoted"`)
+	_ = os.Setenv("maptest", `a=1|b="x|y"|c\=d=2|e=f=g`)
+
+	dictionary := NewDictionary()
+	assert.Nil(t, dictionary.AddLists([]string{"emptytest"}), "unexpected error")
+	assert.Equal(t, []string{}, dictionary.Values()["emptytest"], "empty variable should be an empty list")
+	assert.Nil(t, dictionary.AddMaps([]string{"emptytest"}), "unexpected error")
+	assert.Equal(t, map[string]string{}, dictionary.Values()["emptytest"], "empty variable should be an empty map")
+
+	dictionary.ListSeparator = ";"
+	dictionary.MapPairSeparator = "|"
+	assert.Nil(t, dictionary.AddLists([]string{"listtest"}), "unexpected error")
+	assert.Equal(t, []string{"first", "second;half", "third;quoted"}, dictionary.Values()["listtest"], "unexpected list")
+	assert.Nil(t, dictionary.AddMaps([]string{"maptest"}), "unexpected error")
+	assert.Equal(t, map[string]string{"a": "1", "b": "x|y", "c=d": "2", "e": "f=g"}, dictionary.Values()["maptest"], "unexpected map")
+
+	// Apostrophes inside values are not quotes
+	_ = os.Setenv("listtest", "O'Brien,Smith")
+	_ = os.Setenv("maptest", `name=it's,quoted='a,b',"key"=v`)
+	dictionary = NewDictionary()
+	assert.Nil(t, dictionary.AddLists([]string{"listtest"}), "unexpected error")
+	assert.Equal(t, []string{"O'Brien", "Smith"}, dictionary.Values()["listtest"], "unexpected list")
+	assert.Nil(t, dictionary.AddMaps([]string{"maptest"}), "unexpected error")
+	assert.Equal(t, map[string]string{"name": "it's", "quoted": "a,b", "key": "v"}, dictionary.Values()["maptest"], "unexpected map")
+}
@@ -0,0 +1,212 @@
+package render
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"unicode/utf8"
+)
+
+// toInt converts input to an int. Floating-point numbers are accepted if they have no fraction.
+func toInt(input interface{}) (int, error) {
+	number, err := toNumber(input)
+	if err != nil {
+		return 0, err
+	}
+	switch value := number.(type) {
+	case int64:
+		if value > math.MaxInt32 || value < math.MinInt32 {
+			return 0, errors.New(fmt.Sprintf("integer out of range: %d", value))
+		}
+		return int(value), nil
+	case float64:
+		if value == math.Trunc(value) && value <= math.MaxInt32 && value >= math.MinInt32 {
+			return int(value), nil
+		}
+	}
+	return 0, errors.New(fmt.Sprintf("not an integer: %v", input))
+}
+
+// runeSlicer cuts strings at character (rune) boundaries.
+// Indices outside the string are clamped to the string if clamp is set, otherwise they are an error.
+type runeSlicer struct {
+	clamp bool
+}
+
+// bound checks that index is between 0 and length.
+func (r runeSlicer) bound(index int, length int) (int, error) {
+	if index >= 0 && index <= length {
+		return index, nil
+	}
+	if !r.clamp {
+		return 0, errors.New(fmt.Sprintf("index %d out of range [0:%d]", index, length))
+	}
+	if index < 0 {
+		return 0, nil
+	}
+	return length, nil
+}
+
+// slice returns the characters of s between start and end.
+func (r runeSlicer) slice(s string, start int, end int) (string, error) {
+	runes := []rune(s)
+	start, err := r.bound(start, len(runes))
+	if err != nil {
+		return "", err
+	}
+	end, err = r.bound(end, len(runes))
+	if err != nil {
+		return "", err
+	}
+	if end < start {
+		if !r.clamp {
+			return "", errors.New(fmt.Sprintf("invalid range [%d:%d]", start, end))
+		}
+		end = start
+	}
+	return string(runes[start:end]), nil
+}
+
+// left returns the first n characters of s.
+func (r runeSlicer) left(s string, n interface{}) (string, error) {
+	i, err := toInt(n)
+	if err != nil {
+		return "", err
+	}
+	return r.slice(s, 0, i)
+}
+
+// right returns the last n characters of s.
+func (r runeSlicer) right(s string, n interface{}) (string, error) {
+	i, err := toInt(n)
+	if err != nil {
+		return "", err
+	}
+	if i < 0 && !r.clamp {
+		return "", errors.New(fmt.Sprintf("negative length: %d", i))
+	}
+	length := utf8.RuneCountInString(s)
+	return r.slice(s, length-i, length)
+}
+
+// mid returns l characters of s, starting with the character at index b.
+func (r runeSlicer) mid(s string, b interface{}, l interface{}) (string, error) {
+	start, err := toInt(b)
+	if err != nil {
+		return "", err
+	}
+	length, err := toInt(l)
+	if err != nil {
+		return "", err
+	}
+	if length < 0 && !r.clamp {
+		return "", errors.New(fmt.Sprintf("negative length: %d", length))
+	}
+	return r.slice(s, start, start+length)
+}
+
+// substr returns the characters of s from index start up to, but not including, index end.
+func (r runeSlicer) substr(s string, start interface{}, end interface{}) (string, error) {
+	b, err := toInt(start)
+	if err != nil {
+		return "", err
+	}
+	e, err := toInt(end)
+	if err != nil {
+		return "", err
+	}
+	return r.slice(s, b, e)
+}
+
+// truncate returns at most the first n characters of s. It never fails on a short string.
+func truncate(s string, n interface{}) (string, error) {
+	i, err := toInt(n)
+	if err != nil {
+		return "", err
+	}
+	if i < 0 {
+		return "", errors.New(fmt.Sprintf("negative length: %d", i))
+	}
+	return runeSlicer{clamp: true}.slice(s, 0, i)
+}
+
+// runelen returns the number of characters in s.
+func runelen(s string) int {
+	return utf8.RuneCountInString(s)
+}
+
+// The string library takes the string as the last argument, so it can be used in pipelines:
+// {{ .name | trim | upper }}
+
+func trim(s string) string {
+	return strings.TrimSpace(s)
+}
+
+func trimPrefix(prefix string, s string) string {
+	return strings.TrimPrefix(s, prefix)
+}
+
+func trimSuffix(suffix string, s string) string {
+	return strings.TrimSuffix(s, suffix)
+}
+
+func replace(old string, new string, s string) string {
+	return strings.Replace(s, old, new, -1)
+}
+
+func split(separator string, s string) []string {
+	return strings.Split(s, separator)
+}
+
+// join concatenates the items of any list, separated by separator. Items that are not strings are formatted with fmt.Sprint.
+func join(separator string, list interface{}) (string, error) {
+	value := reflect.ValueOf(list)
+	if value.Kind() != reflect.Slice && value.Kind() != reflect.Array {
+		return "", errors.New(fmt.Sprintf("cannot join %T", list))
+	}
+	items := make([]string, value.Len())
+	for i := range items {
+		items[i] = fmt.Sprint(value.Index(i).Interface())
+	}
+	return strings.Join(items, separator), nil
+}
+
+func contains(substr string, s string) bool {
+	return strings.Contains(s, substr)
+}
+
+func hasPrefix(prefix string, s string) bool {
+	return strings.HasPrefix(s, prefix)
+}
+
+func hasSuffix(suffix string, s string) bool {
+	return strings.HasSuffix(s, suffix)
+}
+
+func repeat(count interface{}, s string) (string, error) {
+	n, err := toInt(count)
+	if err != nil {
+		return "", err
+	}
+	if n < 0 {
+		return "", errors.New(fmt.Sprintf("negative count: %d", n))
+	}
+	return strings.Repeat(s, n), nil
+}
+
+// indent adds spaces to the beginning of each line of s.
+func indent(spaces interface{}, s string) (string, error) {
+	padding, err := repeat(spaces, " ")
+	if err != nil {
+		return "", err
+	}
+	return padding + strings.Replace(s, "\n", "\n"+padding, -1), nil
+}
+
+// nindent is indent with a newline in front, so the indented block can start on its own line.
+func nindent(spaces interface{}, s string) (string, error) {
+	result, err := indent(spaces, s)
+	return "\n" + result, err
+}
@@ -0,0 +1,103 @@
+package render
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRuneSlicer(t *testing.T) {
+	slicer := runeSlicer{}
+	result, err := slicer.left("Árvíztűrő", 3)
+	assert.Nil(t, err, "unexpected error")
+	assert.Equal(t, "Árv", result, "unexpected result")
+	result, err = slicer.right("Árvíztűrő", float64(4))
+	assert.Nil(t, err, "unexpected error")
+	assert.Equal(t, "tűrő", result, "unexpected result")
+	result, err = slicer.mid("Árvíztűrő", 3, "2")
+	assert.Nil(t, err, "unexpected error")
+	assert.Equal(t, "íz", result, "unexpected result")
+	result, err = slicer.substr("Árvíztűrő", 5, 9)
+	assert.Nil(t, err, "unexpected error")
+	assert.Equal(t, "tűrő", result, "unexpected result")
+
+	for _, f := range []func() (string, error){
+		func() (string, error) { return slicer.left("abc", 4) },
+		func() (string, error) { return slicer.left("abc", -1) },
+		func() (string, error) { return slicer.right("abc", 4) },
+		func() (string, error) { return slicer.right("abc", -1) },
+		func() (string, error) { return slicer.mid("abc", 2, 2) },
+		func() (string, error) { return slicer.mid("abc", 1, -1) },
+		func() (string, error) { return slicer.substr("abc", 2, 1) },
+		func() (string, error) { return slicer.left("abc", 1.5) },
+		func() (string, error) { return slicer.left("abc", "x") },
+	} {
+		_, err = f()
+		assert.NotNil(t, err, "expected error")
+	}
+}
+
+func TestRuneSlicerClamp(t *testing.T) {
+	slicer := runeSlicer{clamp: true}
+	for expected, f := range map[string]func() (string, error){
+		"abc": func() (string, error) { return slicer.left("abc", 4) },
+		"":    func() (string, error) { return slicer.left("abc", -1) },
+		"ab":  func() (string, error) { return slicer.right("ab", 4) },
+		"c":   func() (string, error) { return slicer.mid("abc", 2, 2) },
+		"b":   func() (string, error) { return slicer.substr("abc", 1, 2) },
+		"bc":  func() (string, error) { return slicer.substr("abc", 1, 10) },
+	} {
+		result, err := f()
+		assert.Nil(t, err, "unexpected error")
+		assert.Equal(t, expected, result, "unexpected result")
+	}
+	result, err := slicer.mid("abc", 1, -1)
+	assert.Nil(t, err, "unexpected error")
+	assert.Equal(t, "", result, "unexpected result")
+}
+
+func TestTruncateRunelen(t *testing.T) {
+	result, err := truncate("Árvíztűrő", 4)
+	assert.Nil(t, err, "unexpected error")
+	assert.Equal(t, "Árví", result, "unexpected result")
+	result, err = truncate("Ár", 4)
+	assert.Nil(t, err, "unexpected error")
+	assert.Equal(t, "Ár", result, "short strings should not fail")
+	_, err = truncate("Ár", -1)
+	assert.NotNil(t, err, "negative length should fail")
+
+	assert.Equal(t, 9, runelen("Árvíztűrő"), "unexpected length")
+}
+
+func TestStringLibrary(t *testing.T) {
+	assert.Equal(t, "abc", trim(" \tabc\n"), "unexpected trim")
+	assert.Equal(t, "name", trimPrefix("app-", "app-name"), "unexpected trimPrefix")
+	assert.Equal(t, "app", trimSuffix(".conf", "app.conf"), "unexpected trimSuffix")
+	assert.Equal(t, "a-b-c", replace(".", "-", "a.b.c"), "unexpected replace")
+	assert.Equal(t, []string{"a", "b", "c"}, split(",", "a,b,c"), "unexpected split")
+	assert.True(t, contains("b", "abc"), "unexpected contains")
+	assert.True(t, hasPrefix("ab", "abc"), "unexpected hasPrefix")
+	assert.False(t, hasSuffix("ab", "abc"), "unexpected hasSuffix")
+
+	result, err := join(", ", []interface{}{"a", 1, true})
+	assert.Nil(t, err, "unexpected error")
+	assert.Equal(t, "a, 1, true", result, "unexpected join")
+	result, err = join("-", []string{"x", "y"})
+	assert.Nil(t, err, "unexpected error")
+	assert.Equal(t, "x-y", result, "unexpected join")
+	_, err = join("-", "xy")
+	assert.NotNil(t, err, "cannot join a string")
+
+	result, err = repeat(3, "ab")
+	assert.Nil(t, err, "unexpected error")
+	assert.Equal(t, "ababab", result, "unexpected repeat")
+	_, err = repeat(-1, "ab")
+	assert.NotNil(t, err, "negative count should fail")
+
+	result, err = indent(2, "a: 1\nb: 2")
+	assert.Nil(t, err, "unexpected error")
+	assert.Equal(t, "  a: 1\n  b: 2", result, "unexpected indent")
+	result, err = nindent(float64(4), "a: 1")
+	assert.Nil(t, err, "unexpected error")
+	assert.Equal(t, "\n    a: 1", result, "unexpected nindent")
+}